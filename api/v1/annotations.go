@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+const (
+	// AnnotationOptimizedBy is set on pods whose resources were injected at
+	// admission time. The value is the namespace/name of the ResourceOptimizer.
+	AnnotationOptimizedBy = "optimization.stackbalancer.io/optimized-by"
+
+	// AnnotationAllowedSourceNamespaces is set on a namespace to let
+	// ResourceOptimizers in other namespaces target its workloads. The value is
+	// a comma-separated list of namespaces, or "*" for any namespace.
+	AnnotationAllowedSourceNamespaces = "optimization.stackbalancer.io/allowed-source-namespaces"
+
+	// AnnotationExclude set to "true" on a workload opts it out of optimization,
+	// even when a selector or ClusterResourceOptimizer matches it.
+	AnnotationExclude = "optimization.stackbalancer.io/exclude"
+
+	// AnnotationPinCPU set to "true" on a workload keeps its CPU requests and
+	// limits as they are; only memory is recommended.
+	AnnotationPinCPU = "optimization.stackbalancer.io/pin-cpu"
+
+	// AnnotationPinMemory set to "true" on a workload keeps its memory requests
+	// and limits as they are; only CPU is recommended.
+	AnnotationPinMemory = "optimization.stackbalancer.io/pin-memory"
+
+	// AnnotationExcludeContainers on a workload is a comma-separated list of
+	// container names, e.g. sidecars, left out of the analysis and never resized.
+	AnnotationExcludeContainers = "optimization.stackbalancer.io/exclude-containers"
+)
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterResourceOptimizerSpec defines the desired state of ClusterResourceOptimizer
+type ClusterResourceOptimizerSpec struct {
+	// Kind of the matched workloads
+	// +kubebuilder:default=Deployment
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Label selector matched against the workloads. An empty selector matches
+	// every workload of the kind.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Label selector matched against the namespaces to search. An empty
+	// selector matches every namespace.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// priority decides between ClusterResourceOptimizers matching the same
+	// workload; the highest priority wins and ties go to the oldest.
+	// A namespaced ResourceOptimizer always takes precedence.
+	// +kubebuilder:default=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Default policy applied to the matched workloads
+	Policy Policy `json:"policy"`
+
+	// suspend stops metric collection while keeping the last recommendations
+	// in status. Matched workloads stay claimed, so no other
+	// ClusterResourceOptimizer takes over.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// historyLimit is the number of past recommendations kept in status per
+	// workload. A new entry is added whenever the recommended values change.
+	// Set to 0 to disable the history.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	// +kubebuilder:default=10
+	// +optional
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+// ClusterResourceOptimizerStatus defines the observed state of ClusterResourceOptimizer.
+type ClusterResourceOptimizerStatus struct {
+	// conditions represent the current state of the ClusterResourceOptimizer resource.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// observedGeneration is the generation of the spec the status was last computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// targets holds the per-workload results of the matched workloads
+	// +listType=map
+	// +listMapKey=kind
+	// +listMapKey=namespace
+	// +listMapKey=name
+	// +optional
+	Targets []TargetStatus `json:"targets,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// ClusterResourceOptimizer is the Schema for the clusterresourceoptimizers API.
+// It applies a default policy to workloads across namespaces that no namespaced
+// ResourceOptimizer manages.
+type ClusterResourceOptimizer struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ClusterResourceOptimizer
+	// +required
+	Spec ClusterResourceOptimizerSpec `json:"spec"`
+
+	// status defines the observed state of ClusterResourceOptimizer
+	// +optional
+	Status ClusterResourceOptimizerStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterResourceOptimizerList contains a list of ClusterResourceOptimizer
+type ClusterResourceOptimizerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterResourceOptimizer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterResourceOptimizer{}, &ClusterResourceOptimizerList{})
+}
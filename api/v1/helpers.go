@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionAdmissionRisk is True on a target when the API server would
+// reject pods with the recommended resources, e.g. because they exceed a
+// ResourceQuota of the namespace. Recommendations are then not applied.
+const ConditionAdmissionRisk = "AdmissionRisk"
+
+// ResourceRequirements converts the recommendation into container resource
+// requirements. Empty values are left out so unmanaged resources stay untouched.
+func (r *ResourceRecommendation) ResourceRequirements() (corev1.ResourceRequirements, error) {
+	requirements := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+
+	values := []struct {
+		field string
+		value string
+		list  corev1.ResourceList
+		name  corev1.ResourceName
+	}{
+		{"cpu request", r.CPU.Request, requirements.Requests, corev1.ResourceCPU},
+		{"cpu limit", r.CPU.Limit, requirements.Limits, corev1.ResourceCPU},
+		{"memory request", r.Memory.Request, requirements.Requests, corev1.ResourceMemory},
+		{"memory limit", r.Memory.Limit, requirements.Limits, corev1.ResourceMemory},
+	}
+	for _, v := range values {
+		if v.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(v.value)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("invalid %s %q: %w", v.field, v.value, err)
+		}
+		v.list[v.name] = quantity
+	}
+
+	return requirements, nil
+}
+
+// RecommendationFor returns the current recommendation of the optimizer for
+// the given workload, whether it is named by targetRef or matched by
+// targetSelector, or nil if there is none.
+func (r *ResourceOptimizer) RecommendationFor(target TargetRef) *ResourceRecommendation {
+	if r.Spec.TargetRef != nil {
+		if *r.Spec.TargetRef == target {
+			return r.Status.CurrentRecommendation
+		}
+		return nil
+	}
+	for i := range r.Status.Targets {
+		if r.Status.Targets[i].TargetRef == target {
+			return r.Status.Targets[i].Recommendation
+		}
+	}
+	return nil
+}
+
+// TargetConditions returns the conditions of the optimizer for the given
+// workload, or nil if it does not target it.
+func (r *ResourceOptimizer) TargetConditions(target TargetRef) []metav1.Condition {
+	if r.Spec.TargetRef != nil {
+		if *r.Spec.TargetRef == target {
+			return r.Status.Conditions
+		}
+		return nil
+	}
+	for i := range r.Status.Targets {
+		if r.Status.Targets[i].TargetRef == target {
+			return r.Status.Targets[i].Conditions
+		}
+	}
+	return nil
+}
+
+// SourceNamespaceAllowed reports whether a ResourceOptimizer in source may
+// target workloads in the namespace carrying the given annotations. A
+// namespace always grants access to its own optimizers.
+func SourceNamespaceAllowed(target string, annotations map[string]string, source string) bool {
+	if source == target {
+		return true
+	}
+	for _, allowed := range strings.Split(annotations[AnnotationAllowedSourceNamespaces], ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == source {
+			return true
+		}
+	}
+	return false
+}
+
+// AnnotationEnabled reports whether the annotation is set to a true boolean value.
+func AnnotationEnabled(annotations map[string]string, key string) bool {
+	enabled, err := strconv.ParseBool(annotations[key])
+	return err == nil && enabled
+}
+
+// ContainerExcluded reports whether the container is listed in the
+// AnnotationExcludeContainers annotation.
+func ContainerExcluded(annotations map[string]string, container string) bool {
+	for _, name := range strings.Split(annotations[AnnotationExcludeContainers], ",") {
+		if strings.TrimSpace(name) == container {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagedContainers returns the names of the containers that are not excluded
+// by the workload annotations.
+func ManagedContainers(annotations map[string]string, containers []corev1.Container) []string {
+	var names []string
+	for _, container := range containers {
+		if !ContainerExcluded(annotations, container.Name) {
+			names = append(names, container.Name)
+		}
+	}
+	return names
+}
+
+// OwningDeploymentName returns the name of the Deployment a Pod belongs to,
+// or "" if it has none. The ReplicaSet controlling the pod is named after the
+// Deployment suffixed with the pod-template-hash, so no ReplicaSet has to be
+// read, and cached, to follow the chain.
+func OwningDeploymentName(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if owner == nil || owner.Kind != "ReplicaSet" || hash == "" || !strings.HasSuffix(owner.Name, "-"+hash) {
+		return ""
+	}
+	return strings.TrimSuffix(owner.Name, "-"+hash)
+}
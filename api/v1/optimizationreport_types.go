@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OptimizationReportSpec defines what an OptimizationReport aggregates.
+type OptimizationReportSpec struct {
+	// Label selector matched against the namespaces of the optimized
+	// workloads. An empty selector includes every namespace.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// teamLabel is the label key identifying the owning team, read from the
+	// workload and, if missing there, from its namespace. Workloads without
+	// the label are grouped under "unassigned".
+	// +kubebuilder:default=team
+	// +optional
+	TeamLabel string `json:"teamLabel,omitempty"`
+
+	// Number of workloads listed in status.topOffenders. Set to 0 to leave
+	// the list empty.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// +optional
+	TopOffenders *int32 `json:"topOffenders,omitempty"`
+
+	// How often the report is refreshed
+	// +kubebuilder:default="15m"
+	// +optional
+	RefreshInterval metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// ResourceAmounts holds CPU and memory quantities.
+type ResourceAmounts struct {
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+
+	// +optional
+	Memory string `json:"memory,omitempty"`
+}
+
+// ReportSummary aggregates the recommendations of a set of workloads over all
+// their replicas.
+type ReportSummary struct {
+	// Number of workloads with a recommendation
+	Workloads int32 `json:"workloads"`
+
+	// Resources currently requested
+	Requested ResourceAmounts `json:"requested"`
+
+	// Average resources used
+	Used ResourceAmounts `json:"used"`
+
+	// Resources requested once the recommendations are applied. Resources
+	// left unmanaged count at their current requests.
+	Recommended ResourceAmounts `json:"recommended"`
+
+	// Requests exceeding the recommendations, summed over the over-provisioned workloads
+	Waste ResourceAmounts `json:"waste"`
+
+	// Estimated monthly cost, when all included estimates share a currency
+	// +optional
+	Cost *ReportCost `json:"cost,omitempty"`
+}
+
+// ReportCost sums the cost estimates of the included workloads.
+type ReportCost struct {
+	// Currency of the amounts
+	Currency string `json:"currency"`
+
+	// Number of workloads with a cost estimate
+	Workloads int32 `json:"workloads"`
+
+	// Monthly cost of the current requests
+	CurrentMonthly string `json:"currentMonthly"`
+
+	// Monthly cost with the recommended requests
+	RecommendedMonthly string `json:"recommendedMonthly"`
+
+	// Difference between the current and the recommended monthly cost
+	MonthlySavings string `json:"monthlySavings"`
+}
+
+// GroupReport is the summary of the workloads of one namespace or team.
+type GroupReport struct {
+	// Name of the namespace or team
+	Name string `json:"name"`
+
+	ReportSummary `json:",inline"`
+}
+
+// Offender is a workload with a large gap between its requests and the recommendation.
+type Offender struct {
+	TargetRef `json:",inline"`
+
+	// Optimizer that produced the recommendation, as namespace/name for a
+	// ResourceOptimizer and name for a ClusterResourceOptimizer
+	Optimizer string `json:"optimizer"`
+
+	// Requests exceeding the recommendation over all replicas
+	Waste ResourceAmounts `json:"waste"`
+
+	// Estimated monthly savings, when a cost estimate is available
+	// +optional
+	MonthlySavings string `json:"monthlySavings,omitempty"`
+
+	// Currency of the savings
+	// +optional
+	Currency string `json:"currency,omitempty"`
+}
+
+// OptimizationReportStatus holds the aggregated results.
+type OptimizationReportStatus struct {
+	// conditions represent the current state of the report. "Ready" is True
+	// once the report has been refreshed for the current generation.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// observedGeneration is the generation of the spec the report was last computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// lastRefreshed is when the report was last computed
+	// +optional
+	LastRefreshed *metav1.Time `json:"lastRefreshed,omitempty"`
+
+	// total summarizes all included workloads
+	// +optional
+	Total ReportSummary `json:"total,omitzero"`
+
+	// namespaces summarizes the workloads per namespace, by name
+	// +optional
+	Namespaces []GroupReport `json:"namespaces,omitempty"`
+
+	// teams summarizes the workloads per value of spec.teamLabel, by name
+	// +optional
+	Teams []GroupReport `json:"teams,omitempty"`
+
+	// topOffenders lists the workloads with the highest savings, or the
+	// highest CPU waste when costs are not estimated
+	// +optional
+	TopOffenders []Offender `json:"topOffenders,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// OptimizationReport is the Schema for the optimizationreports API. It
+// aggregates the recommendations of all optimizers into a fleet view.
+type OptimizationReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines what the report aggregates
+	// +optional
+	Spec OptimizationReportSpec `json:"spec,omitzero"`
+
+	// status holds the aggregated results
+	// +optional
+	Status OptimizationReportStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// OptimizationReportList contains a list of OptimizationReport
+type OptimizationReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []OptimizationReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OptimizationReport{}, &OptimizationReportList{})
+}
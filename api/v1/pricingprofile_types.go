@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultPricingProfile is the PricingProfile used by optimizers that do not
+// reference one in spec.policy.pricingProfile.
+const DefaultPricingProfile = "default"
+
+// PricingProfileSpec defines the prices used to estimate the cost of workloads.
+type PricingProfileSpec struct {
+	// Currency the rates are expressed in
+	// +kubebuilder:default=USD
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// Rates applied to requests on nodes not matched by any node pool
+	Rates ResourceRates `json:"rates"`
+
+	// nodePools overrides the rates for workloads running on matching nodes,
+	// e.g. per instance type or spot pool. The first matching pool wins.
+	// +optional
+	NodePools []NodePoolPricing `json:"nodePools,omitempty"`
+}
+
+// ResourceRates are the hourly prices of requested resources. Rates are
+// decimal strings, e.g. "0.0316".
+type ResourceRates struct {
+	// Price of one requested vCPU per hour
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	CPUHourly string `json:"cpuHourly"`
+
+	// Price of one requested GiB of memory per hour
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	MemoryGiBHourly string `json:"memoryGiBHourly"`
+}
+
+// NodePoolPricing holds the rates of the nodes matching a label selector.
+type NodePoolPricing struct {
+	// Name of the node pool, for reference in status
+	Name string `json:"name"`
+
+	// Label selector matched against the nodes the workload's pods run on,
+	// e.g. node.kubernetes.io/instance-type
+	NodeSelector metav1.LabelSelector `json:"nodeSelector"`
+
+	// Rates applied on the matching nodes
+	Rates ResourceRates `json:"rates"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// PricingProfile is the Schema for the pricingprofiles API. It holds the
+// prices used to translate recommendations into estimated monthly costs.
+type PricingProfile struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the prices of the profile
+	// +required
+	Spec PricingProfileSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// PricingProfileList contains a list of PricingProfile
+type PricingProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []PricingProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PricingProfile{}, &PricingProfileList{})
+}
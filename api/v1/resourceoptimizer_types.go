@@ -24,27 +24,168 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // ResourceOptimizerSpec defines the desired state of ResourceOptimizer
+// +kubebuilder:validation:XValidation:rule="has(self.targetRef) != has(self.targetSelector)",message="exactly one of targetRef and targetSelector must be set"
 type ResourceOptimizerSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	// The following markers will use OpenAPI v3 schema to validate the value
 	// More info: https://book.kubebuilder.io/reference/markers/crd-validation.html
 
-	TargetRef TargetRef `json:"targetRef"`
-	Policy    Policy    `json:"policy"`
+	// targetRef names a single workload to optimize
+	// +optional
+	TargetRef *TargetRef `json:"targetRef,omitempty"`
+
+	// targetSelector optimizes every workload matching a label selector, with
+	// per-workload results reported in status.targets
+	// +optional
+	TargetSelector *TargetSelector `json:"targetSelector,omitempty"`
+
+	Policy Policy `json:"policy"`
+
+	// updateMode controls how recommendations are acted upon. "Off" only
+	// reports them in status, "Initial" injects them into new pods of the
+	// target at admission time without touching the workload spec.
+	// +kubebuilder:default=Off
+	// +optional
+	UpdateMode UpdateMode `json:"updateMode,omitempty"`
+
+	// suspend stops metric collection, patch publishing and pod mutation while
+	// keeping the last recommendation in status. Set it back to false to resume.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// patchOutput, when set, publishes the current recommendation as a patch in
+	// a ConfigMap so a GitOps pipeline can commit it instead of the operator
+	// mutating the cluster.
+	// +optional
+	PatchOutput *PatchOutput `json:"patchOutput,omitempty"`
+
+	// historyLimit is the number of past recommendations kept in status per
+	// workload. A new entry is added whenever the recommended values change.
+	// Set to 0 to disable the history.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	// +kubebuilder:default=10
+	// +optional
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+// PatchFormat is the format of a published recommendation patch.
+// +kubebuilder:validation:Enum=Kustomize;Helm
+type PatchFormat string
+
+const (
+	// PatchFormatKustomize renders a strategic-merge patch for the target workload.
+	PatchFormatKustomize PatchFormat = "Kustomize"
+
+	// PatchFormatHelm renders a Helm values snippet holding the container resources.
+	PatchFormatHelm PatchFormat = "Helm"
+)
+
+type PatchOutput struct {
+	// Format of the generated patch
+	// +kubebuilder:default=Kustomize
+	// +optional
+	Format PatchFormat `json:"format,omitempty"`
+
+	// Name of the ConfigMap in the ResourceOptimizer namespace that receives the
+	// patch. Defaults to "<resourceoptimizer-name>-patch".
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Dot-separated path in the Helm values under which the resources are written
+	// +kubebuilder:default=resources
+	// +optional
+	ValuesPath string `json:"valuesPath,omitempty"`
 }
 
+// UpdateMode describes how the operator applies its recommendations.
+// +kubebuilder:validation:Enum=Off;Initial
+type UpdateMode string
+
+const (
+	// UpdateModeOff publishes recommendations in status only.
+	UpdateModeOff UpdateMode = "Off"
+
+	// UpdateModeInitial sets container resources on pods when they are created,
+	// similar to the VPA "Initial" mode. Running pods are never changed.
+	UpdateModeInitial UpdateMode = "Initial"
+)
+
 type TargetRef struct {
 	Kind      string `json:"kind"`
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
 }
 
+// TargetSelector selects the workloads of one kind by label, in the
+// ResourceOptimizer namespace or in every namespace matching namespaceSelector.
+type TargetSelector struct {
+	// Kind of the selected workloads
+	// +kubebuilder:default=Deployment
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Label selector matched against the workloads. An empty selector matches
+	// every workload of the kind.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Selects the namespaces to search. Only the ResourceOptimizer namespace
+	// is searched when unset.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
 type Policy struct {
 	Cpu    CPUPolicy    `json:"cpu"`
 	Memory MemoryPolicy `json:"memory"`
+
+	// replicas configures the horizontal sizing suggestions
+	// +optional
+	Replicas *ReplicaPolicy `json:"replicas,omitempty"`
+
+	// hpaConflict decides what happens when a HorizontalPodAutoscaler scales the
+	// target on the utilization of CPU or memory, which is relative to requests.
+	// +kubebuilder:default=Refuse
+	// +optional
+	HPAConflict HPAConflictPolicy `json:"hpaConflict,omitempty"`
+
+	// startupGracePeriod leaves out samples taken within this time after a
+	// container started, so warm-up spikes, e.g. of JVMs, do not inflate the
+	// recommendation. Their peak is reported separately, and the memory limit
+	// still covers it.
+	// +optional
+	StartupGracePeriod *metav1.Duration `json:"startupGracePeriod,omitempty"`
+
+	// idle configures the detection of workloads that use next to no CPU.
+	// Detection runs with the defaults when unset.
+	// +optional
+	Idle *IdlePolicy `json:"idle,omitempty"`
+
+	// pricingProfile names the PricingProfile used to estimate costs. The
+	// profile named "default" is used when unset; without it no costs are
+	// estimated.
+	// +optional
+	PricingProfile string `json:"pricingProfile,omitempty"`
 }
 
+// HPAConflictPolicy describes how to handle an HPA scaling on a managed resource.
+// +kubebuilder:validation:Enum=Refuse;SkipConflicting;AlignTarget
+type HPAConflictPolicy string
+
+const (
+	// HPAConflictRefuse withholds recommendations while the conflict exists.
+	HPAConflictRefuse HPAConflictPolicy = "Refuse"
+
+	// HPAConflictSkipConflicting only manages the resources the HPA does not scale on.
+	HPAConflictSkipConflicting HPAConflictPolicy = "SkipConflicting"
+
+	// HPAConflictAlignTarget uses the HPA CPU target utilization as the policy
+	// target, so requests settle where the HPA expects them. Memory has no
+	// utilization target in the policy and is skipped.
+	HPAConflictAlignTarget HPAConflictPolicy = "AlignTarget"
+)
+
 type CPUPolicy struct {
 	// +kubebuilder:validation:Pattern=`^([0-9]+m|[0-9]+)$`
 	Min string `json:"min"`
@@ -56,6 +197,54 @@ type CPUPolicy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:default=70
 	TargetUtilization int32 `json:"targetUtilization"`
+
+	// Step the recommended request and limit are rounded up to, e.g. "25m" or
+	// "50m". The request stays within min and max: it is rounded up to the
+	// first step at or above min and down to the last step at or below max,
+	// or held to min and max when no step lies between them. Unset leaves
+	// them unrounded
+	// +kubebuilder:validation:Pattern=`^([0-9]+m|[0-9]+)$`
+	// +optional
+	Step string `json:"step,omitempty"`
+}
+
+type ReplicaPolicy struct {
+	// Per-pod CPU request of an alternative pod shape to compare the
+	// recommendation against, e.g. fewer bigger pods
+	// +kubebuilder:validation:Pattern=`^([0-9]+m|[0-9]+)$`
+	// +optional
+	TargetPodCPU string `json:"targetPodCPU,omitempty"`
+
+	// Per-pod memory request of the alternative pod shape
+	// +optional
+	TargetPodMemory string `json:"targetPodMemory,omitempty"`
+
+	// Lower bound for suggested replica counts
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// Upper bound for suggested replica counts, unbounded when unset
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+}
+
+// IdlePolicy sets when a workload is reported as idle, i.e. a candidate for
+// scaling to zero or deletion.
+type IdlePolicy struct {
+	// How long the usage must stay at or below the threshold. At most 168h,
+	// the usage history kept by the operator. Set to 0s to disable detection.
+	// +kubebuilder:default="24h"
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// Per-pod CPU usage at or below which the workload counts as idle
+	// +kubebuilder:validation:Pattern=`^([0-9]+m|[0-9]+)$`
+	// +kubebuilder:default="5m"
+	// +optional
+	CPUThreshold string `json:"cpuThreshold,omitempty"`
 }
 
 type MemoryPolicy struct {
@@ -63,6 +252,12 @@ type MemoryPolicy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:default=20
 	BufferPercent int32 `json:"bufferPercent"`
+
+	// Step the recommended request and limit are rounded up to, e.g. "64Mi"
+	// or "1Gi". Unset leaves them unrounded
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Ki|Mi|Gi|Ti|k|M|G|T)?$`
+	// +optional
+	Step string `json:"step,omitempty"`
 }
 
 // ResourceOptimizerStatus defines the observed state of ResourceOptimizer.
@@ -76,10 +271,10 @@ type ResourceOptimizerStatus struct {
 	// conditions represent the current state of the ResourceOptimizer resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
-	// Standard condition types include:
-	// - "Available": the resource is fully functional
-	// - "Progressing": the resource is being created or updated
-	// - "Degraded": the resource failed to reach or maintain its desired state
+	// Summary condition types, derived from the detailed ones on every update:
+	// - "Ready": recommendations for the current generation are available
+	// - "Progressing": waiting for the target or its metrics
+	// - "Degraded": blocked by something that needs attention
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -87,15 +282,86 @@ type ResourceOptimizerStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// observedGeneration is the generation of the spec the status was last computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// currentRecommendation holds the latest resource optimization recommendation
 	// +optional
 	CurrentRecommendation *ResourceRecommendation `json:"currentRecommendation,omitempty"`
 
+	// patchConfigMap is the name of the ConfigMap holding the published patch
+	// +optional
+	PatchConfigMap string `json:"patchConfigMap,omitempty"`
+
+	// recommendationHistory holds the most recent distinct recommendations, newest first
+	// +optional
+	RecommendationHistory []RecommendationRecord `json:"recommendationHistory,omitempty"`
+
+	// targets holds the per-workload results when spec.targetSelector is used
+	// +listType=map
+	// +listMapKey=kind
+	// +listMapKey=namespace
+	// +listMapKey=name
+	// +optional
+	Targets []TargetStatus `json:"targets,omitempty"`
+
 	// lastOptimized indicates when the workload was last optimized
 	// +optional
 	LastOptimized *metav1.Time `json:"lastOptimized,omitempty"`
 }
 
+// TargetStatus is the result of optimizing one workload selected by spec.targetSelector.
+type TargetStatus struct {
+	TargetRef `json:",inline"`
+
+	// conditions of the analysis of this workload
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// recommendation holds the latest recommendation for this workload
+	// +optional
+	Recommendation *ResourceRecommendation `json:"recommendation,omitempty"`
+
+	// patchConfigMap is the name of the ConfigMap holding the published patch
+	// +optional
+	PatchConfigMap string `json:"patchConfigMap,omitempty"`
+
+	// history holds the most recent distinct recommendations for this workload, newest first
+	// +optional
+	History []RecommendationRecord `json:"history,omitempty"`
+}
+
+// RecommendationRecord is a past recommendation kept in the history, so the
+// drift of recommendations can be followed over time and correlated with rollouts.
+type RecommendationRecord struct {
+	// Recommended CPU values
+	CPU CPURecommendation `json:"cpu"`
+
+	// Recommended memory values
+	Memory MemoryRecommendation `json:"memory"`
+
+	// Confidence level of the recommendation when it was first made (0 to 100 percent)
+	Confidence int32 `json:"confidence"`
+
+	// Reason for the recommendation
+	Reason string `json:"reason"`
+
+	// Timestamp when the recommendation was first made
+	GeneratedAt metav1.Time `json:"generatedAt"`
+
+	// Generation of the target workload the recommendation was computed against
+	// +optional
+	TargetGeneration int64 `json:"targetGeneration,omitempty"`
+
+	// Whether the recommendation was acted upon, by publishing it as a patch
+	// or injecting it into new pods in "Initial" update mode
+	// +optional
+	Applied bool `json:"applied,omitempty"`
+}
+
 type ResourceRecommendation struct {
 	// CPU resource recommendations
 	CPU CPURecommendation `json:"cpu"`
@@ -103,18 +369,200 @@ type ResourceRecommendation struct {
 	// Memory resource recommendations
 	Memory MemoryRecommendation `json:"memory"`
 
+	// Current number of replicas of the target
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// Replica range for pods sized as recommended above
+	// +optional
+	Replicas *ReplicaRecommendation `json:"replicas,omitempty"`
+
+	// Replica range for the alternative pod shape from spec.policy.replicas
+	// +optional
+	TargetShapeReplicas *ReplicaRecommendation `json:"targetShapeReplicas,omitempty"`
+
+	// Peak usage per pod during the startup grace period, left out of the
+	// recommendation
+	// +optional
+	StartupPeak *ResourceAmounts `json:"startupPeak,omitempty"`
+
+	// Requests and average usage per pod of the managed containers when the
+	// recommendation was generated
+	// +optional
+	Observed *ObservedResources `json:"observed,omitempty"`
+
+	// Estimated monthly cost of the target before and after applying the
+	// recommendation, when a PricingProfile is available
+	// +optional
+	Cost *CostEstimate `json:"cost,omitempty"`
+
 	// Confidence level of the recommendation (0 to 100 percent)
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
 	Confidence int32 `json:"confidence"`
 
+	// Measurements the confidence is computed from
+	// +optional
+	ConfidenceComponents *ConfidenceComponents `json:"confidenceComponents,omitempty"`
+
 	// Reason for the recommendation
 	Reason string `json:"reason"`
 
+	// Machine-readable account of how the recommendation was computed
+	// +optional
+	Explanation *RecommendationExplanation `json:"explanation,omitempty"`
+
 	// Timestamp when recommendation was generated
 	GeneratedAt metav1.Time `json:"generatedAt"`
 }
 
+// ConfidenceComponents explain the confidence of a recommendation. Confidence
+// grows with the share of a day covered by the usage history and the number
+// of pods observed, and shrinks with the variation of usage and with gaps in
+// the history.
+type ConfidenceComponents struct {
+	// Time span covered by the usage history
+	HistoryDuration metav1.Duration `json:"historyDuration"`
+
+	// Share of a day covered by the usage history (0 to 100 percent)
+	Coverage int32 `json:"coverage"`
+
+	// Coefficient of variation of the per-pod CPU usage, in percent
+	CPUVariation int32 `json:"cpuVariation"`
+
+	// Coefficient of variation of the per-pod memory usage, in percent
+	MemoryVariation int32 `json:"memoryVariation"`
+
+	// Number of pods observed
+	Pods int32 `json:"pods"`
+
+	// Share of the usage history in which no usage was collected (0 to 100 percent)
+	Gaps int32 `json:"gaps"`
+}
+
+// RecommendationExplanation records the data and the policy inputs a
+// recommendation was computed from, for dashboards and CLIs.
+type RecommendationExplanation struct {
+	// Time span between the first and the last usage sample analyzed
+	Window metav1.Duration `json:"window"`
+
+	// Number of usage samples analyzed, one per pod and collection
+	Samples int32 `json:"samples"`
+
+	// Number of pods the samples were taken from
+	Pods int32 `json:"pods"`
+
+	// Number of samples left out because they were taken during the startup grace period
+	// +optional
+	StartupSamples int32 `json:"startupSamples,omitempty"`
+
+	// How the CPU values were computed
+	CPU CPUExplanation `json:"cpu"`
+
+	// How the memory values were computed
+	Memory MemoryExplanation `json:"memory"`
+}
+
+// UsageStatistics summarize the per-pod usage samples of one resource.
+type UsageStatistics struct {
+	// Average usage
+	Average string `json:"average"`
+
+	// Highest usage
+	Peak string `json:"peak"`
+
+	// Median usage
+	P50 string `json:"p50"`
+
+	// 90th percentile of usage
+	P90 string `json:"p90"`
+
+	// 99th percentile of usage
+	P99 string `json:"p99"`
+
+	// Number of values replaced by their median as outliers
+	// +optional
+	OutliersRejected int32 `json:"outliersRejected,omitempty"`
+}
+
+// ResourceExplanation is how the recommended values of one resource were
+// derived from usage.
+type ResourceExplanation struct {
+	// Statistics of the analyzed usage
+	Usage UsageStatistics `json:"usage"`
+
+	// Request derived from usage and policy, before any bound was applied
+	Target string `json:"target"`
+
+	// Bounds that changed the request, in the order they were applied:
+	// "min" or "max" of the policy, "step" when it was rounded up, or
+	// "limitRange" when the request or limit was fit into a LimitRange of the
+	// target namespace
+	// +optional
+	ClampedBy []string `json:"clampedBy,omitempty"`
+
+	// What the limit was derived from: "request" for a fixed headroom over the
+	// request, "peak" for a headroom over the peak usage, or "startupPeak" for
+	// the peak usage during the startup grace period
+	LimitBasis string `json:"limitBasis"`
+
+	// Why the resource is left unmanaged, if it is: "HPA", "Pinned" or
+	// "MemoryGrowth"
+	// +optional
+	Unmanaged string `json:"unmanaged,omitempty"`
+}
+
+// CPUExplanation is how the CPU values were computed.
+type CPUExplanation struct {
+	ResourceExplanation `json:",inline"`
+
+	// Policy inputs used
+	Policy CPUPolicy `json:"policy"`
+}
+
+// MemoryExplanation is how the memory values were computed.
+type MemoryExplanation struct {
+	ResourceExplanation `json:",inline"`
+
+	// Policy inputs used
+	Policy MemoryPolicy `json:"policy"`
+}
+
+// ObservedResources describes a pod of the target as the analysis saw it.
+type ObservedResources struct {
+	// Requests per pod
+	Requests ResourceAmounts `json:"requests"`
+
+	// Average usage per pod
+	Usage ResourceAmounts `json:"usage"`
+}
+
+// CostEstimate is the monthly cost of the requests of all replicas of the
+// target. Amounts are decimal strings with two fractional digits.
+type CostEstimate struct {
+	// Name of the PricingProfile the estimate is based on
+	PricingProfile string `json:"pricingProfile"`
+
+	// Node pool of the profile whose rates were applied, empty for the default rates
+	// +optional
+	NodePool string `json:"nodePool,omitempty"`
+
+	// Currency of the amounts
+	Currency string `json:"currency"`
+
+	// Monthly cost of the current requests
+	CurrentMonthly string `json:"currentMonthly"`
+
+	// Monthly cost with the recommended requests
+	RecommendedMonthly string `json:"recommendedMonthly"`
+
+	// Difference between the current and the recommended monthly cost,
+	// negative when the recommendation grows the target
+	MonthlySavings string `json:"monthlySavings"`
+}
+
+// CPURecommendation holds the recommended CPU values. Both are empty when CPU
+// is left unmanaged, e.g. because an HPA scales on CPU utilization.
 type CPURecommendation struct {
 	// Recommended CPU request
 	Request string `json:"request"`
@@ -123,6 +571,30 @@ type CPURecommendation struct {
 	Limit string `json:"limit"`
 }
 
+// ReplicaRecommendation sizes the workload horizontally for one pod shape, so
+// "fewer bigger pods" and "more smaller pods" can be compared by their totals.
+type ReplicaRecommendation struct {
+	// Per-pod CPU request the range is computed for
+	PodCPU string `json:"podCPU"`
+
+	// Per-pod memory request the range is computed for
+	PodMemory string `json:"podMemory"`
+
+	// Replicas needed to serve the average aggregate usage
+	MinReplicas int32 `json:"minReplicas"`
+
+	// Replicas needed to serve the peak aggregate usage
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// CPU requested by MinReplicas pods of this shape
+	TotalCPU string `json:"totalCPU"`
+
+	// Memory requested by MinReplicas pods of this shape
+	TotalMemory string `json:"totalMemory"`
+}
+
+// MemoryRecommendation holds the recommended memory values. Both are empty when
+// memory is left unmanaged.
 type MemoryRecommendation struct {
 	// Recommended memory request
 	Request string `json:"request"`
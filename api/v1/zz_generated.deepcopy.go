@@ -25,79 +25,721 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUExplanation) DeepCopyInto(out *CPUExplanation) {
+	*out = *in
+	in.ResourceExplanation.DeepCopyInto(&out.ResourceExplanation)
+	out.Policy = in.Policy
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUExplanation.
+func (in *CPUExplanation) DeepCopy() *CPUExplanation {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUExplanation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUPolicy) DeepCopyInto(out *CPUPolicy) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPolicy.
-func (in *CPUPolicy) DeepCopy() *CPUPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUPolicy.
+func (in *CPUPolicy) DeepCopy() *CPUPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPURecommendation) DeepCopyInto(out *CPURecommendation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPURecommendation.
+func (in *CPURecommendation) DeepCopy() *CPURecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(CPURecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceOptimizer) DeepCopyInto(out *ClusterResourceOptimizer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceOptimizer.
+func (in *ClusterResourceOptimizer) DeepCopy() *ClusterResourceOptimizer {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceOptimizer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterResourceOptimizer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceOptimizerList) DeepCopyInto(out *ClusterResourceOptimizerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterResourceOptimizer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceOptimizerList.
+func (in *ClusterResourceOptimizerList) DeepCopy() *ClusterResourceOptimizerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceOptimizerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterResourceOptimizerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceOptimizerSpec) DeepCopyInto(out *ClusterResourceOptimizerSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	in.Policy.DeepCopyInto(&out.Policy)
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceOptimizerSpec.
+func (in *ClusterResourceOptimizerSpec) DeepCopy() *ClusterResourceOptimizerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceOptimizerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceOptimizerStatus) DeepCopyInto(out *ClusterResourceOptimizerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceOptimizerStatus.
+func (in *ClusterResourceOptimizerStatus) DeepCopy() *ClusterResourceOptimizerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceOptimizerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfidenceComponents) DeepCopyInto(out *ConfidenceComponents) {
+	*out = *in
+	out.HistoryDuration = in.HistoryDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfidenceComponents.
+func (in *ConfidenceComponents) DeepCopy() *ConfidenceComponents {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfidenceComponents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupReport) DeepCopyInto(out *GroupReport) {
+	*out = *in
+	in.ReportSummary.DeepCopyInto(&out.ReportSummary)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupReport.
+func (in *GroupReport) DeepCopy() *GroupReport {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdlePolicy) DeepCopyInto(out *IdlePolicy) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdlePolicy.
+func (in *IdlePolicy) DeepCopy() *IdlePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdlePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryExplanation) DeepCopyInto(out *MemoryExplanation) {
+	*out = *in
+	in.ResourceExplanation.DeepCopyInto(&out.ResourceExplanation)
+	out.Policy = in.Policy
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryExplanation.
+func (in *MemoryExplanation) DeepCopy() *MemoryExplanation {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryExplanation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPolicy) DeepCopyInto(out *MemoryPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPolicy.
+func (in *MemoryPolicy) DeepCopy() *MemoryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryRecommendation) DeepCopyInto(out *MemoryRecommendation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryRecommendation.
+func (in *MemoryRecommendation) DeepCopy() *MemoryRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolPricing) DeepCopyInto(out *NodePoolPricing) {
+	*out = *in
+	in.NodeSelector.DeepCopyInto(&out.NodeSelector)
+	out.Rates = in.Rates
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolPricing.
+func (in *NodePoolPricing) DeepCopy() *NodePoolPricing {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolPricing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedResources) DeepCopyInto(out *ObservedResources) {
+	*out = *in
+	out.Requests = in.Requests
+	out.Usage = in.Usage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedResources.
+func (in *ObservedResources) DeepCopy() *ObservedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Offender) DeepCopyInto(out *Offender) {
+	*out = *in
+	out.TargetRef = in.TargetRef
+	out.Waste = in.Waste
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Offender.
+func (in *Offender) DeepCopy() *Offender {
+	if in == nil {
+		return nil
+	}
+	out := new(Offender)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationReport) DeepCopyInto(out *OptimizationReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationReport.
+func (in *OptimizationReport) DeepCopy() *OptimizationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OptimizationReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationReportList) DeepCopyInto(out *OptimizationReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OptimizationReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationReportList.
+func (in *OptimizationReportList) DeepCopy() *OptimizationReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OptimizationReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationReportSpec) DeepCopyInto(out *OptimizationReportSpec) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.TopOffenders != nil {
+		in, out := &in.TopOffenders, &out.TopOffenders
+		*out = new(int32)
+		**out = **in
+	}
+	out.RefreshInterval = in.RefreshInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationReportSpec.
+func (in *OptimizationReportSpec) DeepCopy() *OptimizationReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptimizationReportStatus) DeepCopyInto(out *OptimizationReportStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRefreshed != nil {
+		in, out := &in.LastRefreshed, &out.LastRefreshed
+		*out = (*in).DeepCopy()
+	}
+	in.Total.DeepCopyInto(&out.Total)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]GroupReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]GroupReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopOffenders != nil {
+		in, out := &in.TopOffenders, &out.TopOffenders
+		*out = make([]Offender, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptimizationReportStatus.
+func (in *OptimizationReportStatus) DeepCopy() *OptimizationReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OptimizationReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchOutput) DeepCopyInto(out *PatchOutput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchOutput.
+func (in *PatchOutput) DeepCopy() *PatchOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(CPUPolicy)
+	out := new(PatchOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CPURecommendation) DeepCopyInto(out *CPURecommendation) {
+func (in *Policy) DeepCopyInto(out *Policy) {
 	*out = *in
+	out.Cpu = in.Cpu
+	out.Memory = in.Memory
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(ReplicaPolicy)
+		**out = **in
+	}
+	if in.StartupGracePeriod != nil {
+		in, out := &in.StartupGracePeriod, &out.StartupGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Idle != nil {
+		in, out := &in.Idle, &out.Idle
+		*out = new(IdlePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPURecommendation.
-func (in *CPURecommendation) DeepCopy() *CPURecommendation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
+func (in *Policy) DeepCopy() *Policy {
 	if in == nil {
 		return nil
 	}
-	out := new(CPURecommendation)
+	out := new(Policy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryPolicy) DeepCopyInto(out *MemoryPolicy) {
+func (in *PricingProfile) DeepCopyInto(out *PricingProfile) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPolicy.
-func (in *MemoryPolicy) DeepCopy() *MemoryPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingProfile.
+func (in *PricingProfile) DeepCopy() *PricingProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryPolicy)
+	out := new(PricingProfile)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PricingProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryRecommendation) DeepCopyInto(out *MemoryRecommendation) {
+func (in *PricingProfileList) DeepCopyInto(out *PricingProfileList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PricingProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryRecommendation.
-func (in *MemoryRecommendation) DeepCopy() *MemoryRecommendation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingProfileList.
+func (in *PricingProfileList) DeepCopy() *PricingProfileList {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryRecommendation)
+	out := new(PricingProfileList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PricingProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Policy) DeepCopyInto(out *Policy) {
+func (in *PricingProfileSpec) DeepCopyInto(out *PricingProfileSpec) {
 	*out = *in
-	out.Cpu = in.Cpu
+	out.Rates = in.Rates
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolPricing, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PricingProfileSpec.
+func (in *PricingProfileSpec) DeepCopy() *PricingProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PricingProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommendationExplanation) DeepCopyInto(out *RecommendationExplanation) {
+	*out = *in
+	out.Window = in.Window
+	in.CPU.DeepCopyInto(&out.CPU)
+	in.Memory.DeepCopyInto(&out.Memory)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationExplanation.
+func (in *RecommendationExplanation) DeepCopy() *RecommendationExplanation {
+	if in == nil {
+		return nil
+	}
+	out := new(RecommendationExplanation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommendationRecord) DeepCopyInto(out *RecommendationRecord) {
+	*out = *in
+	out.CPU = in.CPU
 	out.Memory = in.Memory
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Policy.
-func (in *Policy) DeepCopy() *Policy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationRecord.
+func (in *RecommendationRecord) DeepCopy() *RecommendationRecord {
 	if in == nil {
 		return nil
 	}
-	out := new(Policy)
+	out := new(RecommendationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaPolicy) DeepCopyInto(out *ReplicaPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaPolicy.
+func (in *ReplicaPolicy) DeepCopy() *ReplicaPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaRecommendation) DeepCopyInto(out *ReplicaRecommendation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaRecommendation.
+func (in *ReplicaRecommendation) DeepCopy() *ReplicaRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportCost) DeepCopyInto(out *ReportCost) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportCost.
+func (in *ReportCost) DeepCopy() *ReportCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportSummary) DeepCopyInto(out *ReportSummary) {
+	*out = *in
+	out.Requested = in.Requested
+	out.Used = in.Used
+	out.Recommended = in.Recommended
+	out.Waste = in.Waste
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(ReportCost)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportSummary.
+func (in *ReportSummary) DeepCopy() *ReportSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAmounts) DeepCopyInto(out *ResourceAmounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAmounts.
+func (in *ResourceAmounts) DeepCopy() *ResourceAmounts {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAmounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceExplanation) DeepCopyInto(out *ResourceExplanation) {
+	*out = *in
+	out.Usage = in.Usage
+	if in.ClampedBy != nil {
+		in, out := &in.ClampedBy, &out.ClampedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceExplanation.
+func (in *ResourceExplanation) DeepCopy() *ResourceExplanation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceExplanation)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -107,7 +749,7 @@ func (in *ResourceOptimizer) DeepCopyInto(out *ResourceOptimizer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -164,8 +806,27 @@ func (in *ResourceOptimizerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceOptimizerSpec) DeepCopyInto(out *ResourceOptimizerSpec) {
 	*out = *in
-	out.TargetRef = in.TargetRef
-	out.Policy = in.Policy
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(TargetRef)
+		**out = **in
+	}
+	if in.TargetSelector != nil {
+		in, out := &in.TargetSelector, &out.TargetSelector
+		*out = new(TargetSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Policy.DeepCopyInto(&out.Policy)
+	if in.PatchOutput != nil {
+		in, out := &in.PatchOutput, &out.PatchOutput
+		*out = new(PatchOutput)
+		**out = **in
+	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceOptimizerSpec.
@@ -193,6 +854,20 @@ func (in *ResourceOptimizerStatus) DeepCopyInto(out *ResourceOptimizerStatus) {
 		*out = new(ResourceRecommendation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RecommendationHistory != nil {
+		in, out := &in.RecommendationHistory, &out.RecommendationHistory
+		*out = make([]RecommendationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.LastOptimized != nil {
 		in, out := &in.LastOptimized, &out.LastOptimized
 		*out = (*in).DeepCopy()
@@ -209,11 +884,61 @@ func (in *ResourceOptimizerStatus) DeepCopy() *ResourceOptimizerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRates) DeepCopyInto(out *ResourceRates) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRates.
+func (in *ResourceRates) DeepCopy() *ResourceRates {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
 	*out = *in
 	out.CPU = in.CPU
 	out.Memory = in.Memory
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(ReplicaRecommendation)
+		**out = **in
+	}
+	if in.TargetShapeReplicas != nil {
+		in, out := &in.TargetShapeReplicas, &out.TargetShapeReplicas
+		*out = new(ReplicaRecommendation)
+		**out = **in
+	}
+	if in.StartupPeak != nil {
+		in, out := &in.StartupPeak, &out.StartupPeak
+		*out = new(ResourceAmounts)
+		**out = **in
+	}
+	if in.Observed != nil {
+		in, out := &in.Observed, &out.Observed
+		*out = new(ObservedResources)
+		**out = **in
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(CostEstimate)
+		**out = **in
+	}
+	if in.ConfidenceComponents != nil {
+		in, out := &in.ConfidenceComponents, &out.ConfidenceComponents
+		*out = new(ConfidenceComponents)
+		**out = **in
+	}
+	if in.Explanation != nil {
+		in, out := &in.Explanation, &out.Explanation
+		*out = new(RecommendationExplanation)
+		(*in).DeepCopyInto(*out)
+	}
 	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
 }
 
@@ -241,3 +966,74 @@ func (in *TargetRef) DeepCopy() *TargetRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetSelector) DeepCopyInto(out *TargetSelector) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSelector.
+func (in *TargetSelector) DeepCopy() *TargetSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
+	*out = *in
+	out.TargetRef = in.TargetRef
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Recommendation != nil {
+		in, out := &in.Recommendation, &out.Recommendation
+		*out = new(ResourceRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RecommendationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetStatus.
+func (in *TargetStatus) DeepCopy() *TargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageStatistics) DeepCopyInto(out *UsageStatistics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageStatistics.
+func (in *UsageStatistics) DeepCopy() *UsageStatistics {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageStatistics)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -37,6 +37,7 @@ import (
 
 	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
 	"github.com/stackbalancer/cost-optimizer-operator/internal/controller"
+	webhookv1 "github.com/stackbalancer/cost-optimizer-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -178,13 +179,44 @@ func main() {
 		os.Exit(1)
 	}
 	// Start the ResourceOptimizer operator
-	if err := (&controller.ResourceOptimizerReconciler{
+	optimizerReconciler := &controller.ResourceOptimizerReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	if err := optimizerReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ResourceOptimizer")
 		os.Exit(1)
 	}
+	if err := (&controller.ClusterResourceOptimizerReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Optimizer: optimizerReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterResourceOptimizer")
+		os.Exit(1)
+	}
+	if err := (&controller.OptimizationReportReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OptimizationReport")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1.SetupPodWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+		if err := webhookv1.SetupResourceOptimizerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ResourceOptimizer")
+			os.Exit(1)
+		}
+		if err := webhookv1.SetupClusterResourceOptimizerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterResourceOptimizer")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,265 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// boundLimitRange marks a recommendation changed to fit a LimitRange.
+const boundLimitRange = "limitRange"
+
+// quotaResources maps the quota resources a recommendation can affect to the
+// recommended resource and whether the quota counts limits or requests.
+var quotaResources = []struct {
+	quota    corev1.ResourceName
+	resource corev1.ResourceName
+	limits   bool
+}{
+	{corev1.ResourceCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceRequestsCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceLimitsCPU, corev1.ResourceCPU, true},
+	{corev1.ResourceMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceRequestsMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceLimitsMemory, corev1.ResourceMemory, true},
+}
+
+// +kubebuilder:rbac:groups="",resources=limitranges;resourcequotas,verbs=get;list;watch
+
+// applyAdmissionConstraints makes the recommendation admissible in the
+// namespace of the deployment. Values outside the container or pod min and max
+// of a LimitRange, or limits above its maximum limit-to-request ratio, are
+// clamped, even beyond the bounds of the policy, since the API server would
+// reject them otherwise. Pod items bound the sum over all containers, so the
+// containers left unmanaged take their share first. Defaults of a LimitRange only fill in missing values and never
+// apply, as recommendations carry both requests and limits.
+//
+// A ResourceQuota cannot be satisfied by clamping, as its headroom is shared
+// with other workloads. When applying the recommendation to all replicas would
+// exceed one, the AdmissionRisk condition is set and the recommendation is not
+// applied until the quota allows it.
+func (r *ResourceOptimizerReconciler) applyAdmissionConstraints(ctx context.Context, owner client.Object, deployment *appsv1.Deployment, recommendation *optimizationv1.ResourceRecommendation, target *optimizationv1.TargetStatus) error {
+	requirements, err := recommendation.ResourceRequirements()
+	if err != nil {
+		return err
+	}
+
+	limitRanges := &corev1.LimitRangeList{}
+	if err := r.List(ctx, limitRanges, client.InNamespace(deployment.Namespace)); err != nil {
+		return fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+	unmanaged := unmanagedResources(deployment)
+	var clamped []string
+	for _, limitRange := range limitRanges.Items {
+		for _, item := range limitRange.Spec.Limits {
+			var others corev1.ResourceRequirements
+			switch item.Type {
+			case corev1.LimitTypeContainer:
+			case corev1.LimitTypePod:
+				others = unmanaged
+			default:
+				continue
+			}
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if clampToLimitRange(requirements, name, item, others) && !slices.Contains(clamped, string(name)) {
+					clamped = append(clamped, string(name))
+				}
+			}
+		}
+	}
+	for _, name := range clamped {
+		request, limit := requirements.Requests[corev1.ResourceName(name)], requirements.Limits[corev1.ResourceName(name)]
+		if name == string(corev1.ResourceCPU) {
+			recommendation.CPU = optimizationv1.CPURecommendation{Request: request.String(), Limit: limit.String()}
+			recommendation.Explanation.CPU.ClampedBy = append(recommendation.Explanation.CPU.ClampedBy, boundLimitRange)
+		} else {
+			recommendation.Memory = optimizationv1.MemoryRecommendation{Request: request.String(), Limit: limit.String()}
+			recommendation.Explanation.Memory.ClampedBy = append(recommendation.Explanation.Memory.ClampedBy, boundLimitRange)
+		}
+	}
+
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(deployment.Namespace)); err != nil {
+		return fmt.Errorf("failed to list ResourceQuotas: %w", err)
+	}
+	violations := quotaViolations(quotas.Items, deployment, requirements)
+
+	if len(violations) == 0 {
+		message := fmt.Sprintf("The recommendation fits the LimitRanges and ResourceQuotas of namespace %s", deployment.Namespace)
+		if len(clamped) > 0 {
+			message += fmt.Sprintf(" after clamping %s to a LimitRange", strings.Join(clamped, " and "))
+		}
+		setCondition(&target.Conditions, owner.GetGeneration(), optimizationv1.ConditionAdmissionRisk, metav1.ConditionFalse,
+			"Admissible", message)
+		return nil
+	}
+
+	message := "Pods with the recommended resources would be rejected: " + strings.Join(violations, "; ")
+	if !meta.IsStatusConditionTrue(target.Conditions, optimizationv1.ConditionAdmissionRisk) {
+		r.recorder.Eventf(owner, corev1.EventTypeWarning, "AdmissionRisk", "Deployment %s: %s",
+			client.ObjectKeyFromObject(deployment), message)
+	}
+	setCondition(&target.Conditions, owner.GetGeneration(), optimizationv1.ConditionAdmissionRisk, metav1.ConditionTrue,
+		"QuotaExceeded", message)
+	return nil
+}
+
+// dropRecommendation clears the recommendation of target along with its
+// AdmissionRisk condition, which only describes that recommendation.
+func dropRecommendation(target *optimizationv1.TargetStatus) {
+	target.Recommendation = nil
+	meta.RemoveStatusCondition(&target.Conditions, optimizationv1.ConditionAdmissionRisk)
+}
+
+// clampToLimitRange fits the request and limit of the named resource into a
+// LimitRange item and reports whether either changed. The item bounds the
+// recommended values plus those of others, the containers sharing the pod
+// that the recommendation does not cover. Resources left unmanaged by the
+// recommendation are not touched.
+func clampToLimitRange(requirements corev1.ResourceRequirements, name corev1.ResourceName, item corev1.LimitRangeItem, others corev1.ResourceRequirements) bool {
+	request, hasRequest := requirements.Requests[name]
+	limit, hasLimit := requirements.Limits[name]
+	if !hasRequest && !hasLimit {
+		return false
+	}
+
+	// CPU is compared in millicores, memory in bytes
+	value, quantity := func(q resource.Quantity) int64 { return q.Value() }, func(v int64) resource.Quantity {
+		return *resource.NewQuantity(v, resource.BinarySI)
+	}
+	if name == corev1.ResourceCPU {
+		value, quantity = func(q resource.Quantity) int64 { return q.MilliValue() }, func(v int64) resource.Quantity {
+			return *resource.NewMilliQuantity(v, resource.DecimalSI)
+		}
+	}
+	otherRequest, otherLimit := value(others.Requests[name]), value(others.Limits[name])
+
+	changed := false
+	set := func(list corev1.ResourceList, quantity resource.Quantity) {
+		list[name] = quantity
+		changed = true
+	}
+	if minimum, ok := item.Min[name]; ok {
+		if hasRequest && value(request)+otherRequest < value(minimum) {
+			request = quantity(value(minimum) - otherRequest)
+			set(requirements.Requests, request)
+		}
+		if hasLimit && value(limit)+otherLimit < value(minimum) {
+			limit = quantity(value(minimum) - otherLimit)
+			set(requirements.Limits, limit)
+		}
+	}
+	if maximum, ok := item.Max[name]; ok {
+		// Without room left by the other containers no value is admissible
+		if hasRequest && value(request)+otherRequest > value(maximum) && value(maximum) > otherRequest {
+			request = quantity(value(maximum) - otherRequest)
+			set(requirements.Requests, request)
+		}
+		if hasLimit && value(limit)+otherLimit > value(maximum) && value(maximum) > otherLimit {
+			limit = quantity(value(maximum) - otherLimit)
+			set(requirements.Limits, limit)
+		}
+	}
+	if ratio, ok := item.MaxLimitRequestRatio[name]; ok && hasRequest && hasLimit {
+		highest := (value(request)+otherRequest)*ratio.MilliValue()/1000 - otherLimit
+		if value(limit) > highest {
+			set(requirements.Limits, quantity(highest))
+		}
+	}
+	return changed
+}
+
+// unmanagedResources sums the requests and limits of the containers in the
+// pod template that recommendations do not cover.
+func unmanagedResources(deployment *appsv1.Deployment) corev1.ResourceRequirements {
+	totals := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+	add := func(totals, list corev1.ResourceList) {
+		for name, quantity := range list {
+			total := totals[name]
+			total.Add(quantity)
+			totals[name] = total
+		}
+	}
+	managed := optimizationv1.ManagedContainers(deployment.Annotations, deployment.Spec.Template.Spec.Containers)
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if !slices.Contains(managed, container.Name) {
+			add(totals.Requests, container.Resources.Requests)
+			add(totals.Limits, container.Resources.Limits)
+		}
+	}
+	return totals
+}
+
+// quotaViolations describes the ResourceQuotas that could not hold the
+// deployment with the recommended resources on all replicas. Scoped quotas
+// are skipped, since whether they apply depends on the pods.
+func quotaViolations(quotas []corev1.ResourceQuota, deployment *appsv1.Deployment, requirements corev1.ResourceRequirements) []string {
+	replicas := int64(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = int64(*deployment.Spec.Replicas)
+	}
+	currentRequests, currentLimits := managedRequests(deployment), managedLimits(deployment)
+
+	var violations []string
+	for _, quota := range quotas {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		for _, tracked := range quotaResources {
+			hard, ok := quota.Status.Hard[tracked.quota]
+			if !ok {
+				continue
+			}
+			recommended, current := requirements.Requests, currentRequests
+			if tracked.limits {
+				recommended, current = requirements.Limits, currentLimits
+			}
+			value, ok := recommended[tracked.resource]
+			if !ok {
+				continue
+			}
+
+			// Grow the used amount by the change of every replica
+			growth := value.DeepCopy()
+			growth.Sub(current[tracked.resource])
+			if growth.Sign() <= 0 {
+				continue
+			}
+			growth.Mul(replicas)
+			used := quota.Status.Used[tracked.quota]
+			headroom := hard.DeepCopy()
+			headroom.Sub(used)
+			if growth.Cmp(headroom) > 0 {
+				violations = append(violations, fmt.Sprintf("ResourceQuota %s: %s would grow by %s with %s left",
+					quota.Name, tracked.quota, growth.String(), headroom.String()))
+			}
+		}
+	}
+	return violations
+}
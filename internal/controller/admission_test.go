@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Admission constraints", func() {
+	var (
+		ctx            context.Context
+		owner          *optimizationv1.ResourceOptimizer
+		deployment     *appsv1.Deployment
+		recommendation *optimizationv1.ResourceRecommendation
+		target         *optimizationv1.TargetStatus
+		recorder       *record.FakeRecorder
+	)
+
+	apply := func(objects ...client.Object) {
+		r := newTestReconciler(objects...)
+		recorder = r.recorder.(*record.FakeRecorder)
+		Expect(r.applyAdmissionConstraints(ctx, owner, deployment, recommendation, target)).To(Succeed())
+	}
+
+	quota := func(name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		owner = &optimizationv1.ResourceOptimizer{ObjectMeta: metav1.ObjectMeta{Name: "api-service", Namespace: "production"}}
+		deployment = testDeployment(2, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		})
+		recommendation = &optimizationv1.ResourceRecommendation{
+			CPU:         optimizationv1.CPURecommendation{Request: "800m", Limit: "1200m"},
+			Memory:      optimizationv1.MemoryRecommendation{Request: "256Mi", Limit: "1Gi"},
+			Explanation: &optimizationv1.RecommendationExplanation{},
+		}
+		target = &optimizationv1.TargetStatus{}
+	})
+
+	It("Should clamp the recommendation to the container LimitRange", func() {
+		apply(&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "production"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:                 corev1.LimitTypeContainer,
+				Max:                  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				Min:                  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("384Mi")},
+				MaxLimitRequestRatio: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2")},
+			}}},
+		})
+
+		Expect(recommendation.CPU).To(Equal(optimizationv1.CPURecommendation{Request: "500m", Limit: "500m"}))
+		Expect(recommendation.Memory).To(Equal(optimizationv1.MemoryRecommendation{Request: "384Mi", Limit: "768Mi"}))
+		Expect(recommendation.Explanation.CPU.ClampedBy).To(Equal([]string{"limitRange"}))
+		Expect(recommendation.Explanation.Memory.ClampedBy).To(Equal([]string{"limitRange"}))
+
+		condition := meta.FindStatusCondition(target.Conditions, optimizationv1.ConditionAdmissionRisk)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("after clamping cpu and memory"))
+	})
+
+	It("Should clamp the recommendation to the pod LimitRange, leaving room for excluded containers", func() {
+		deployment.Annotations = map[string]string{optimizationv1.AnnotationExcludeContainers: "proxy"}
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{
+			Name: "proxy",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+			},
+		})
+		apply(&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "production"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type: corev1.LimitTypePod,
+				Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				Min:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}}},
+		})
+
+		Expect(recommendation.CPU).To(Equal(optimizationv1.CPURecommendation{Request: "800m", Limit: "800m"}))
+		Expect(recommendation.Memory).To(Equal(optimizationv1.MemoryRecommendation{Request: "448Mi", Limit: "1Gi"}))
+		Expect(recommendation.Explanation.CPU.ClampedBy).To(Equal([]string{"limitRange"}))
+		Expect(recommendation.Explanation.Memory.ClampedBy).To(Equal([]string{"limitRange"}))
+	})
+
+	It("Should leave unmanaged resources alone", func() {
+		recommendation.CPU = optimizationv1.CPURecommendation{}
+		apply(&corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "production"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type: corev1.LimitTypeContainer,
+				Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			}}},
+		})
+
+		Expect(recommendation.CPU).To(Equal(optimizationv1.CPURecommendation{}))
+		Expect(recommendation.Explanation.CPU.ClampedBy).To(BeEmpty())
+	})
+
+	It("Should report a recommendation the ResourceQuota cannot hold", func() {
+		recommendation.CPU.Request = "1500m"
+		scoped := quota("high-priority", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")})
+		scoped.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopePriorityClass}
+		apply(
+			quota("compute", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3")},
+				corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2500m")}),
+			scoped,
+		)
+
+		condition := meta.FindStatusCondition(target.Conditions, optimizationv1.ConditionAdmissionRisk)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("QuotaExceeded"))
+		Expect(condition.Message).To(ContainSubstring("ResourceQuota compute: requests.cpu would grow by 1 with 500m left"))
+		Expect(condition.Message).NotTo(ContainSubstring("high-priority"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("AdmissionRisk")))
+	})
+
+	It("Should accept recommendations that shrink the workload", func() {
+		apply(quota("compute", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")}))
+
+		Expect(meta.IsStatusConditionFalse(target.Conditions, optimizationv1.ConditionAdmissionRisk)).To(BeTrue())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	Context("When the target no longer gets a recommendation", func() {
+		BeforeEach(func() {
+			target.Recommendation = recommendation
+			target.Conditions = []metav1.Condition{{
+				Type:   optimizationv1.ConditionAdmissionRisk,
+				Status: metav1.ConditionTrue,
+				Reason: "QuotaExceeded",
+			}}
+		})
+
+		It("Should drop the AdmissionRisk condition of an excluded workload", func() {
+			deployment.Annotations = map[string]string{optimizationv1.AnnotationExclude: "true"}
+			r := newTestReconciler(deployment)
+
+			Expect(r.analyzeAndOptimize(ctx, owner, optimizationv1.Policy{}, deployment, target)).To(Succeed())
+			Expect(target.Recommendation).To(BeNil())
+			Expect(meta.FindStatusCondition(target.Conditions, optimizationv1.ConditionAdmissionRisk)).To(BeNil())
+		})
+
+		It("Should drop the AdmissionRisk condition when an HPA conflict withholds the recommendation", func() {
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "production"},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api-service"},
+					MaxReplicas:    10,
+					Metrics: []autoscalingv2.MetricSpec{{
+						Type: autoscalingv2.ResourceMetricSourceType,
+						Resource: &autoscalingv2.ResourceMetricSource{
+							Name:   corev1.ResourceCPU,
+							Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To[int32](60)},
+						},
+					}},
+				},
+			}
+			r := newTestReconciler(deployment, hpa)
+
+			Expect(r.analyzeAndOptimize(ctx, owner, optimizationv1.Policy{}, deployment, target)).To(Succeed())
+			Expect(target.Recommendation).To(BeNil())
+			Expect(meta.IsStatusConditionFalse(target.Conditions, "OptimizationReady")).To(BeTrue())
+			Expect(meta.FindStatusCondition(target.Conditions, optimizationv1.ConditionAdmissionRisk)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// targetAuthorized reports whether a ResourceOptimizer in sourceNamespace may
+// read metrics of and act on workloads in targetNamespace. Other namespaces
+// must opt in with the AnnotationAllowedSourceNamespaces annotation, so
+// create rights in one namespace do not extend to another.
+func (r *ResourceOptimizerReconciler) targetAuthorized(ctx context.Context, sourceNamespace, targetNamespace string) (bool, error) {
+	if sourceNamespace == targetNamespace {
+		return true, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: targetNamespace}, namespace); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get namespace %s: %w", targetNamespace, err)
+	}
+	return optimizationv1.SourceNamespaceAllowed(targetNamespace, namespace.Annotations, sourceNamespace), nil
+}
+
+// unauthorizedMessage explains how to grant a source namespace access to target namespaces.
+func unauthorizedMessage(sourceNamespace string, targetNamespaces ...string) string {
+	subject := "Namespace " + targetNamespaces[0] + " does"
+	if len(targetNamespaces) > 1 {
+		subject = "Namespaces " + strings.Join(targetNamespaces, ", ") + " do"
+	}
+	return fmt.Sprintf("%s not allow ResourceOptimizers from %s; add it to the %s annotation",
+		subject, sourceNamespace, optimizationv1.AnnotationAllowedSourceNamespaces)
+}
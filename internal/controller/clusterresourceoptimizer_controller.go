@@ -0,0 +1,427 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// ClusterResourceOptimizerReconciler reconciles a ClusterResourceOptimizer object
+type ClusterResourceOptimizerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	// Optimizer performs the per-workload analysis shared with ResourceOptimizers
+	Optimizer *ResourceOptimizerReconciler
+}
+
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=clusterresourceoptimizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=clusterresourceoptimizers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=clusterresourceoptimizers/finalizers,verbs=update
+
+// Reconcile analyzes every workload matched by the ClusterResourceOptimizer
+// under its default policy. Workloads managed by a namespaced
+// ResourceOptimizer or by a ClusterResourceOptimizer with higher precedence
+// are listed as Superseded.
+func (r *ClusterResourceOptimizerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	clusterOptimizer := &optimizationv1.ClusterResourceOptimizer{}
+	if err := r.Get(ctx, req.NamespacedName, clusterOptimizer); err != nil {
+		log.Error(err, "Failed to get clusterResourceOptimizer")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log.Info("Reconciling clusterResourceOptimizer", "priority", clusterOptimizer.Spec.Priority, "policy", clusterOptimizer.Spec.Policy)
+
+	if clusterOptimizer.Spec.Suspend {
+		log.Info("ClusterResourceOptimizer is suspended")
+		setCondition(
+			&clusterOptimizer.Status.Conditions,
+			clusterOptimizer.Generation,
+			"Suspended",
+			metav1.ConditionTrue,
+			"Suspended",
+			"Optimization is suspended, the last recommendations are kept",
+		)
+		return ctrl.Result{}, r.updateStatus(ctx, clusterOptimizer)
+	}
+	setCondition(
+		&clusterOptimizer.Status.Conditions,
+		clusterOptimizer.Generation,
+		"Suspended",
+		metav1.ConditionFalse,
+		"Active",
+		"Optimization is active",
+	)
+
+	deployments, namespaces, err := r.matchDeployments(ctx, clusterOptimizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(deployments) == 0 {
+		setCondition(
+			&clusterOptimizer.Status.Conditions,
+			clusterOptimizer.Generation,
+			"DeploymentReady",
+			metav1.ConditionFalse,
+			"TargetNotFound",
+			"No Deployment matches the selectors",
+		)
+		clusterOptimizer.Status.Targets = nil
+		if err := r.updateStatus(ctx, clusterOptimizer); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	}
+
+	setCondition(
+		&clusterOptimizer.Status.Conditions,
+		clusterOptimizer.Generation,
+		"DeploymentReady",
+		metav1.ConditionTrue,
+		"TargetFound",
+		fmt.Sprintf("%d Deployments match the selectors", len(deployments)),
+	)
+
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := r.List(ctx, optimizers); err != nil {
+		return ctrl.Result{}, err
+	}
+	clusterOptimizers := &optimizationv1.ClusterResourceOptimizerList{}
+	if err := r.List(ctx, clusterOptimizers); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous := make(map[optimizationv1.TargetRef]optimizationv1.TargetStatus, len(clusterOptimizer.Status.Targets))
+	for _, target := range clusterOptimizer.Status.Targets {
+		previous[target.TargetRef] = target
+	}
+
+	targets := make([]optimizationv1.TargetStatus, 0, len(deployments))
+	recommended := 0
+	for i := range deployments {
+		deployment := &deployments[i]
+		ref := optimizationv1.TargetRef{Kind: "Deployment", Name: deployment.Name, Namespace: deployment.Namespace}
+		target := previous[ref]
+		target.TargetRef = ref
+
+		owner := r.precedingOptimizer(ctx, clusterOptimizer, optimizers.Items, clusterOptimizers.Items,
+			deployment, namespaces[deployment.Namespace])
+		switch {
+		case owner != "":
+			dropRecommendation(&target)
+			setCondition(
+				&target.Conditions,
+				clusterOptimizer.Generation,
+				"OptimizationReady",
+				metav1.ConditionFalse,
+				"Superseded",
+				fmt.Sprintf("Deployment is managed by %s", owner),
+			)
+		default:
+			if err := r.Optimizer.analyzeAndOptimize(ctx, clusterOptimizer, clusterOptimizer.Spec.Policy, deployment, &target); err != nil {
+				log.Error(err, "Failed to optimize matched workload", "deployment", client.ObjectKeyFromObject(deployment))
+				setCondition(
+					&target.Conditions,
+					clusterOptimizer.Generation,
+					"OptimizationReady",
+					metav1.ConditionFalse,
+					"AnalysisFailed",
+					err.Error(),
+				)
+			} else {
+				recordHistory(&target, deployment.Generation, false, historyLimit(clusterOptimizer.Spec.HistoryLimit))
+			}
+		}
+
+		if target.Recommendation != nil {
+			recommended++
+		}
+		targets = append(targets, target)
+	}
+	if trimmed := trimTargets(targets, maxTargetsSize); trimmed != "" {
+		setCondition(
+			&clusterOptimizer.Status.Conditions,
+			clusterOptimizer.Generation,
+			"StatusTrimmed",
+			metav1.ConditionTrue,
+			"SizeLimitReached",
+			fmt.Sprintf("Dropped %s of %d targets to keep the status within the object size limit", trimmed, len(targets)),
+		)
+	} else {
+		setCondition(
+			&clusterOptimizer.Status.Conditions,
+			clusterOptimizer.Generation,
+			"StatusTrimmed",
+			metav1.ConditionFalse,
+			"WithinSizeLimit",
+			"The status of all targets is complete",
+		)
+	}
+	clusterOptimizer.Status.Targets = targets
+
+	message := fmt.Sprintf("%d of %d matched Deployments have a recommendation", recommended, len(targets))
+	if recommended > 0 {
+		setCondition(
+			&clusterOptimizer.Status.Conditions,
+			clusterOptimizer.Generation,
+			"OptimizationReady",
+			metav1.ConditionTrue,
+			"RecommendationGenerated",
+			message,
+		)
+	} else {
+		setCondition(
+			&clusterOptimizer.Status.Conditions,
+			clusterOptimizer.Generation,
+			"OptimizationReady",
+			metav1.ConditionFalse,
+			"NoRecommendations",
+			message,
+		)
+	}
+
+	if err := r.updateStatus(ctx, clusterOptimizer); err != nil {
+		log.Error(err, "Failed to update ClusterResourceOptimizer status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Reconciliation complete", "targets", len(targets), "recommended", recommended)
+
+	return ctrl.Result{RequeueAfter: time.Minute * 15}, nil
+}
+
+// matchDeployments returns the Deployments matched by the ClusterResourceOptimizer
+// together with every matched namespace by name.
+func (r *ClusterResourceOptimizerReconciler) matchDeployments(ctx context.Context, clusterOptimizer *optimizationv1.ClusterResourceOptimizer) ([]appsv1.Deployment, map[string]*corev1.Namespace, error) {
+	if clusterOptimizer.Spec.Kind != "" && clusterOptimizer.Spec.Kind != "Deployment" {
+		return nil, nil, fmt.Errorf("unsupported target kind %q", clusterOptimizer.Spec.Kind)
+	}
+
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(&clusterOptimizer.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&clusterOptimizer.Spec.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid selector: %w", err)
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+		return nil, nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaces := make(map[string]*corev1.Namespace, len(namespaceList.Items))
+	for i := range namespaceList.Items {
+		namespaces[namespaceList.Items[i].Name] = &namespaceList.Items[i]
+	}
+
+	deploymentList := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deploymentList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, nil, fmt.Errorf("failed to list Deployments: %w", err)
+	}
+
+	var deployments []appsv1.Deployment
+	for _, deployment := range deploymentList.Items {
+		if _, ok := namespaces[deployment.Namespace]; ok {
+			deployments = append(deployments, deployment)
+		}
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		if deployments[i].Namespace != deployments[j].Namespace {
+			return deployments[i].Namespace < deployments[j].Namespace
+		}
+		return deployments[i].Name < deployments[j].Name
+	})
+
+	return deployments, namespaces, nil
+}
+
+// precedingOptimizer describes the optimizer that manages the deployment
+// instead of clusterOptimizer, or returns an empty string if there is none.
+// Any namespaced ResourceOptimizer allowed to act on the deployment wins over
+// ClusterResourceOptimizers. An optimizer whose selectors cannot be evaluated
+// is skipped, so it does not stall every ClusterResourceOptimizer.
+func (r *ClusterResourceOptimizerReconciler) precedingOptimizer(
+	ctx context.Context,
+	clusterOptimizer *optimizationv1.ClusterResourceOptimizer,
+	optimizers []optimizationv1.ResourceOptimizer,
+	clusterOptimizers []optimizationv1.ClusterResourceOptimizer,
+	deployment *appsv1.Deployment,
+	namespace *corev1.Namespace,
+) string {
+	log := logf.FromContext(ctx)
+
+	for i := range optimizers {
+		selects, err := optimizerSelects(&optimizers[i], deployment, namespace)
+		if err != nil {
+			log.Error(err, "Skipping ResourceOptimizer with an invalid selector", "resourceOptimizer", client.ObjectKeyFromObject(&optimizers[i]))
+			continue
+		}
+		if selects {
+			return "ResourceOptimizer " + client.ObjectKeyFromObject(&optimizers[i]).String()
+		}
+	}
+
+	for i := range clusterOptimizers {
+		other := &clusterOptimizers[i]
+		if other.Name == clusterOptimizer.Name || !clusterOptimizerPrecedes(other, clusterOptimizer) {
+			continue
+		}
+		selects, err := clusterOptimizerSelects(other, deployment, labels.Set(namespace.Labels))
+		if err != nil {
+			log.Error(err, "Skipping ClusterResourceOptimizer with an invalid selector", "clusterResourceOptimizer", other.Name)
+			continue
+		}
+		if selects {
+			return "ClusterResourceOptimizer " + other.Name
+		}
+	}
+
+	return ""
+}
+
+// updateStatus derives the summary conditions and observedGeneration and
+// writes the status. Failed writes are surfaced as an event and metric.
+func (r *ClusterResourceOptimizerReconciler) updateStatus(ctx context.Context, clusterOptimizer *optimizationv1.ClusterResourceOptimizer) error {
+	summarizeConditions(&clusterOptimizer.Status.Conditions, clusterOptimizer.Generation)
+	clusterOptimizer.Status.ObservedGeneration = clusterOptimizer.Generation
+
+	err := patchStatus(ctx, r.Client, clusterOptimizer, func(latest *optimizationv1.ClusterResourceOptimizer) {
+		latest.Status = clusterOptimizer.Status
+	})
+	if client.IgnoreNotFound(err) != nil {
+		statusUpdateFailuresTotal.WithLabelValues("ClusterResourceOptimizer").Inc()
+		r.recorder.Eventf(clusterOptimizer, corev1.EventTypeWarning, "StatusUpdateFailed", "Failed to update status: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterResourceOptimizerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("cost-optimizer-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&optimizationv1.ClusterResourceOptimizer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&appsv1.Deployment{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForDeployment),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{},
+				predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForPod),
+			builder.WithPredicates(podReadinessChanged)).
+		Watches(&optimizationv1.ResourceOptimizer{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForResourceOptimizer),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("clusterresourceoptimizer").
+		Complete(r)
+}
+
+// requestsForDeployment maps a Deployment to the ClusterResourceOptimizers
+// matching it. There are few of them, so they are filtered without an index.
+func (r *ClusterResourceOptimizerReconciler) requestsForDeployment(ctx context.Context, obj client.Object) []reconcile.Request {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	clusterOptimizers := &optimizationv1.ClusterResourceOptimizerList{}
+	if err := r.List(ctx, clusterOptimizers); err != nil {
+		log.Error(err, "Failed to list ClusterResourceOptimizers")
+		return nil
+	}
+	if len(clusterOptimizers.Items) == 0 {
+		return nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: deployment.Namespace}, namespace); err != nil {
+		log.Error(err, "Failed to get namespace", "namespace", deployment.Namespace)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range clusterOptimizers.Items {
+		selects, err := clusterOptimizerSelects(&clusterOptimizers.Items[i], deployment, labels.Set(namespace.Labels))
+		if err != nil || !selects {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterOptimizers.Items[i])})
+	}
+	return requests
+}
+
+// requestsForPod maps a Pod to the ClusterResourceOptimizers matching its Deployment.
+func (r *ClusterResourceOptimizerReconciler) requestsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	deployment := owningDeployment(ctx, r.Client, obj)
+	if deployment == nil {
+		return nil
+	}
+	return r.requestsForDeployment(ctx, deployment)
+}
+
+// requestsForResourceOptimizer maps a ResourceOptimizer to the
+// ClusterResourceOptimizers that may list its targets, so Superseded follows
+// ResourceOptimizers being created, changed or deleted. A targetRef maps
+// through its Deployment, while a selector may match any workload and maps to
+// every ClusterResourceOptimizer.
+func (r *ClusterResourceOptimizerReconciler) requestsForResourceOptimizer(ctx context.Context, obj client.Object) []reconcile.Request {
+	optimizer, ok := obj.(*optimizationv1.ResourceOptimizer)
+	if !ok {
+		return nil
+	}
+
+	if targetRef := optimizer.Spec.TargetRef; targetRef != nil {
+		deployment := &appsv1.Deployment{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: targetRef.Namespace, Name: targetRef.Name}, deployment); err != nil {
+			return nil
+		}
+		return r.requestsForDeployment(ctx, deployment)
+	}
+
+	clusterOptimizers := &optimizationv1.ClusterResourceOptimizerList{}
+	if err := r.List(ctx, clusterOptimizers); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ClusterResourceOptimizers")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(clusterOptimizers.Items))
+	for i := range clusterOptimizers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterOptimizers.Items[i])})
+	}
+	return requests
+}
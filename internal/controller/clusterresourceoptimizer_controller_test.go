@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("ClusterResourceOptimizer Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-cluster-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{Name: resourceName}
+
+		BeforeEach(func() {
+			requireEnvtest()
+			By("creating the custom resource for the Kind ClusterResourceOptimizer")
+			err := k8sClient.Get(ctx, typeNamespacedName, &optimizationv1.ClusterResourceOptimizer{})
+			if err != nil && errors.IsNotFound(err) {
+				resource := &optimizationv1.ClusterResourceOptimizer{
+					ObjectMeta: metav1.ObjectMeta{Name: resourceName},
+					Spec: optimizationv1.ClusterResourceOptimizerSpec{
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "does-not-exist"}},
+						Policy: optimizationv1.Policy{
+							Cpu:    optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+							Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			if k8sClient == nil {
+				return
+			}
+			resource := &optimizationv1.ClusterResourceOptimizer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+			By("Cleanup the specific resource instance ClusterResourceOptimizer")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should report that no workload matches", func() {
+			controllerReconciler := &ClusterResourceOptimizerReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &optimizationv1.ClusterResourceOptimizer{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, "DeploymentReady")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("TargetNotFound"))
+		})
+	})
+
+	Context("When deciding which optimizer manages a workload", func() {
+		var (
+			ctx        context.Context
+			namespace  *corev1.Namespace
+			deployment *appsv1.Deployment
+		)
+
+		clusterOptimizer := func(name string, priority int32, created time.Time) *optimizationv1.ClusterResourceOptimizer {
+			return &optimizationv1.ClusterResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+				Spec: optimizationv1.ClusterResourceOptimizerSpec{
+					Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api-service"}},
+					Priority: priority,
+					Policy: optimizationv1.Policy{
+						Cpu:    optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+						Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+					},
+				},
+			}
+		}
+		resourceOptimizer := func(name, ns string) *optimizationv1.ResourceOptimizer {
+			return &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+				Spec: optimizationv1.ResourceOptimizerSpec{
+					TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+				},
+			}
+		}
+		invalidSelector := metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: "Matches", Values: []string{"api-service"}},
+		}}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}}
+			deployment = testDeployment(2, nil)
+			deployment.Labels = map[string]string{"app": "api-service"}
+		})
+
+		It("should let a ResourceOptimizer in the namespace take precedence", func() {
+			r := &ClusterResourceOptimizerReconciler{}
+			own := clusterOptimizer("cluster-defaults", 100, time.Now())
+			owner := r.precedingOptimizer(ctx, own,
+				[]optimizationv1.ResourceOptimizer{*resourceOptimizer("api-service-optimizer", "production")},
+				[]optimizationv1.ClusterResourceOptimizer{*own}, deployment, namespace)
+			Expect(owner).To(Equal("ResourceOptimizer production/api-service-optimizer"))
+		})
+
+		It("should ignore a ResourceOptimizer of a namespace that is not allowed", func() {
+			r := &ClusterResourceOptimizerReconciler{}
+			own := clusterOptimizer("cluster-defaults", 0, time.Now())
+			owner := r.precedingOptimizer(ctx, own,
+				[]optimizationv1.ResourceOptimizer{*resourceOptimizer("api-service-optimizer", "maintenance")},
+				[]optimizationv1.ClusterResourceOptimizer{*own}, deployment, namespace)
+			Expect(owner).To(BeEmpty())
+
+			namespace.Annotations = map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "maintenance"}
+			owner = r.precedingOptimizer(ctx, own,
+				[]optimizationv1.ResourceOptimizer{*resourceOptimizer("api-service-optimizer", "maintenance")},
+				[]optimizationv1.ClusterResourceOptimizer{*own}, deployment, namespace)
+			Expect(owner).To(Equal("ResourceOptimizer maintenance/api-service-optimizer"))
+		})
+
+		It("should order ClusterResourceOptimizers by priority, then age", func() {
+			r := &ClusterResourceOptimizerReconciler{}
+			now := time.Now()
+			low := clusterOptimizer("low", 0, now.Add(-time.Hour))
+			high := clusterOptimizer("high", 10, now)
+			newer := clusterOptimizer("newer", 10, now.Add(time.Minute))
+			all := []optimizationv1.ClusterResourceOptimizer{*low, *high, *newer}
+
+			Expect(r.precedingOptimizer(ctx, low, nil, all, deployment, namespace)).To(Equal("ClusterResourceOptimizer high"))
+			Expect(r.precedingOptimizer(ctx, newer, nil, all, deployment, namespace)).To(Equal("ClusterResourceOptimizer high"))
+			Expect(r.precedingOptimizer(ctx, high, nil, all, deployment, namespace)).To(BeEmpty())
+		})
+
+		It("should skip optimizers whose selector is invalid", func() {
+			r := &ClusterResourceOptimizerReconciler{}
+			own := clusterOptimizer("cluster-defaults", 0, time.Now())
+			broken := resourceOptimizer("broken", "production")
+			broken.Spec.TargetRef = nil
+			broken.Spec.TargetSelector = &optimizationv1.TargetSelector{Selector: invalidSelector}
+			brokenCluster := clusterOptimizer("broken", 10, time.Now())
+			brokenCluster.Spec.Selector = invalidSelector
+
+			owner := r.precedingOptimizer(ctx, own, []optimizationv1.ResourceOptimizer{*broken},
+				[]optimizationv1.ClusterResourceOptimizer{*own, *brokenCluster}, deployment, namespace)
+			Expect(owner).To(BeEmpty())
+		})
+
+		It("should list a workload managed by a ResourceOptimizer as superseded", func() {
+			own := clusterOptimizer("cluster-defaults", 0, time.Now())
+			broken := resourceOptimizer("broken", "production")
+			broken.Spec.TargetRef = nil
+			broken.Spec.TargetSelector = &optimizationv1.TargetSelector{Selector: invalidSelector}
+			optimizer := newTestReconciler(namespace, deployment, own, broken,
+				resourceOptimizer("api-service-optimizer", "production"))
+			r := &ClusterResourceOptimizerReconciler{
+				Client:    optimizer.Client,
+				Scheme:    optimizer.Scheme,
+				recorder:  record.NewFakeRecorder(20),
+				Optimizer: optimizer,
+			}
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(own)})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &optimizationv1.ClusterResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(own), updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(HaveLen(1))
+			condition := meta.FindStatusCondition(updated.Status.Targets[0].Conditions, "OptimizationReady")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("Superseded"))
+			Expect(condition.Message).To(ContainSubstring("production/api-service-optimizer"))
+		})
+
+		It("should enqueue the ClusterResourceOptimizers a ResourceOptimizer may supersede", func() {
+			own := clusterOptimizer("cluster-defaults", 0, time.Now())
+			other := clusterOptimizer("web-defaults", 0, time.Now())
+			other.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+			r := &ClusterResourceOptimizerReconciler{
+				Client: newTestClientBuilder(namespace, deployment, own, other).Build(),
+			}
+
+			byName := resourceOptimizer("api-service-optimizer", "production")
+			Expect(r.requestsForResourceOptimizer(ctx, byName)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster-defaults"}},
+			))
+
+			byName.Spec.TargetRef.Name = "missing"
+			Expect(r.requestsForResourceOptimizer(ctx, byName)).To(BeEmpty())
+
+			bySelector := resourceOptimizer("team-optimizer", "production")
+			bySelector.Spec.TargetRef = nil
+			bySelector.Spec.TargetSelector = &optimizationv1.TargetSelector{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api-service"}},
+			}
+			Expect(r.requestsForResourceOptimizer(ctx, bySelector)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster-defaults"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "web-defaults"}},
+			))
+		})
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// Summary condition types, derived from the detailed conditions by
+// summarizeConditions so they are consistent on every status write.
+const (
+	// conditionReady is True once recommendations for the current generation
+	// are available and published.
+	conditionReady = "Ready"
+
+	// conditionProgressing is True while the optimizer waits for something
+	// expected to resolve on its own, such as the target or its metrics.
+	conditionProgressing = "Progressing"
+
+	// conditionDegraded is True when the optimizer is blocked by something
+	// that needs attention, such as a failed analysis or a missing grant.
+	conditionDegraded = "Degraded"
+)
+
+// setCondition records a condition observed for the given generation. The
+// transition time only changes when the status does.
+func setCondition(conditions *[]metav1.Condition, generation int64, condType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// summarizeConditions sets Ready, Progressing and Degraded from the detailed
+// conditions, so tools like `kubectl wait --for=condition=Ready` and Argo CD
+// health checks see a consistent state.
+func summarizeConditions(conditions *[]metav1.Condition, generation int64) {
+	if meta.IsStatusConditionTrue(*conditions, "Suspended") {
+		message := "Optimization is suspended"
+		setCondition(conditions, generation, conditionReady, metav1.ConditionFalse, "Suspended", message)
+		setCondition(conditions, generation, conditionProgressing, metav1.ConditionFalse, "Suspended", message)
+		setCondition(conditions, generation, conditionDegraded, metav1.ConditionFalse, "Suspended", message)
+		return
+	}
+
+	var progressing, degraded *metav1.Condition
+	if c := meta.FindStatusCondition(*conditions, "Unauthorized"); c != nil && c.Status == metav1.ConditionTrue {
+		degraded = &metav1.Condition{Reason: "Unauthorized", Message: c.Message}
+	}
+	if c := meta.FindStatusCondition(*conditions, "DeploymentReady"); c != nil && c.Status == metav1.ConditionFalse {
+		progressing = &metav1.Condition{Reason: "WaitingForTarget", Message: c.Message}
+	}
+	optimization := meta.FindStatusCondition(*conditions, "OptimizationReady")
+	if optimization != nil && optimization.Status == metav1.ConditionFalse {
+		switch optimization.Reason {
+		case "NoMetricsData", "NoRecommendations", "WarmingUp":
+			if progressing == nil {
+				progressing = &metav1.Condition{Reason: "WaitingForMetrics", Message: optimization.Message}
+			}
+		case "Excluded":
+		default:
+			if degraded == nil {
+				degraded = &metav1.Condition{Reason: optimization.Reason, Message: optimization.Message}
+			}
+		}
+	}
+	if c := meta.FindStatusCondition(*conditions, optimizationv1.ConditionAdmissionRisk); c != nil && c.Status == metav1.ConditionTrue && degraded == nil {
+		degraded = &metav1.Condition{Reason: "AdmissionRisk", Message: c.Message}
+	}
+	if c := meta.FindStatusCondition(*conditions, "PatchPublished"); c != nil && c.Status == metav1.ConditionFalse && degraded == nil {
+		degraded = &metav1.Condition{Reason: "PublishFailed", Message: c.Message}
+	}
+
+	if progressing != nil {
+		setCondition(conditions, generation, conditionProgressing, metav1.ConditionTrue, progressing.Reason, progressing.Message)
+	} else {
+		setCondition(conditions, generation, conditionProgressing, metav1.ConditionFalse, "ReconcileComplete", "Nothing to wait for")
+	}
+	if degraded != nil {
+		setCondition(conditions, generation, conditionDegraded, metav1.ConditionTrue, degraded.Reason, degraded.Message)
+	} else {
+		setCondition(conditions, generation, conditionDegraded, metav1.ConditionFalse, "AsExpected", "No issues found")
+	}
+
+	switch {
+	case degraded != nil:
+		setCondition(conditions, generation, conditionReady, metav1.ConditionFalse, degraded.Reason, degraded.Message)
+	case progressing != nil:
+		setCondition(conditions, generation, conditionReady, metav1.ConditionFalse, progressing.Reason, progressing.Message)
+	case optimization == nil || optimization.Status != metav1.ConditionTrue:
+		reason, message := "NotAnalyzed", "No recommendation has been generated yet"
+		if optimization != nil {
+			reason, message = optimization.Reason, optimization.Message
+		}
+		setCondition(conditions, generation, conditionReady, metav1.ConditionFalse, reason, message)
+	default:
+		setCondition(conditions, generation, conditionReady, metav1.ConditionTrue, "RecommendationReady", optimization.Message)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Status conditions", func() {
+	var conditions []metav1.Condition
+
+	BeforeEach(func() {
+		conditions = nil
+		setCondition(&conditions, 3, "DeploymentReady", metav1.ConditionTrue, "TargetFound", "Target Deployment exists")
+		setCondition(&conditions, 3, "OptimizationReady", metav1.ConditionTrue, "RecommendationGenerated", "ok")
+	})
+
+	It("Should keep the transition time while the status is unchanged", func() {
+		conditions[0].LastTransitionTime = metav1.Unix(0, 0)
+		setCondition(&conditions, 4, "DeploymentReady", metav1.ConditionTrue, "TargetFound", "Target Deployment exists")
+
+		condition := meta.FindStatusCondition(conditions, "DeploymentReady")
+		Expect(condition.LastTransitionTime).To(Equal(metav1.Unix(0, 0)))
+		Expect(condition.ObservedGeneration).To(Equal(int64(4)))
+	})
+
+	It("Should report Ready once a recommendation is available", func() {
+		summarizeConditions(&conditions, 3)
+
+		Expect(meta.IsStatusConditionTrue(conditions, conditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conditions, conditionProgressing)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conditions, conditionDegraded)).To(BeTrue())
+		Expect(meta.FindStatusCondition(conditions, conditionReady).ObservedGeneration).To(Equal(int64(3)))
+	})
+
+	It("Should turn Ready off again after a later failure", func() {
+		summarizeConditions(&conditions, 3)
+		setCondition(&conditions, 3, "OptimizationReady", metav1.ConditionFalse, "AnalysisFailed", "metrics API unavailable")
+		summarizeConditions(&conditions, 3)
+
+		ready := meta.FindStatusCondition(conditions, conditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("AnalysisFailed"))
+		Expect(meta.IsStatusConditionTrue(conditions, conditionDegraded)).To(BeTrue())
+	})
+
+	It("Should report Degraded rather than a failed publish while admission would fail", func() {
+		setCondition(&conditions, 3, optimizationv1.ConditionAdmissionRisk, metav1.ConditionTrue, "QuotaExceeded", "quota")
+		setCondition(&conditions, 3, "PatchPublished", metav1.ConditionFalse, "AdmissionRisk", "not published")
+		summarizeConditions(&conditions, 3)
+
+		Expect(meta.FindStatusCondition(conditions, conditionDegraded).Reason).To(Equal("AdmissionRisk"))
+		Expect(meta.IsStatusConditionFalse(conditions, conditionReady)).To(BeTrue())
+	})
+
+	It("Should report Progressing while waiting for the target", func() {
+		setCondition(&conditions, 3, "DeploymentReady", metav1.ConditionFalse, "TargetNotFound", "Target Deployment does not exist yet")
+		summarizeConditions(&conditions, 3)
+
+		Expect(meta.FindStatusCondition(conditions, conditionProgressing).Reason).To(Equal("WaitingForTarget"))
+		Expect(meta.IsStatusConditionFalse(conditions, conditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conditions, conditionDegraded)).To(BeTrue())
+	})
+
+	It("Should report a suspended optimizer as not Ready", func() {
+		setCondition(&conditions, 3, "Suspended", metav1.ConditionTrue, "Suspended", "suspended")
+		summarizeConditions(&conditions, 3)
+
+		Expect(meta.FindStatusCondition(conditions, conditionReady).Reason).To(Equal("Suspended"))
+	})
+})
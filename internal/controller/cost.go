@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// hoursPerMonth is the average number of hours in a month, as used by cloud price lists.
+const hoursPerMonth = 730
+
+const conditionCostEstimated = "CostEstimated"
+
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=pricingprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// updateCostEstimate sets the cost of the recommendation and the
+// CostEstimated condition of the target. A failed estimate, e.g. because of a
+// missing PricingProfile or invalid rates, leaves the cost unset rather than
+// holding back the recommendation. A warning event is recorded on owner when
+// estimation starts failing.
+func (r *ResourceOptimizerReconciler) updateCostEstimate(ctx context.Context, owner client.Object, policy optimizationv1.Policy, deployment *appsv1.Deployment, recommendation *optimizationv1.ResourceRecommendation, target *optimizationv1.TargetStatus) {
+	cost, err := r.estimateCost(ctx, policy, deployment, recommendation)
+	recommendation.Cost = cost
+	switch {
+	case err != nil:
+		if c := meta.FindStatusCondition(target.Conditions, conditionCostEstimated); c == nil || c.Reason != "EstimationFailed" {
+			r.recorder.Eventf(owner, corev1.EventTypeWarning, "CostEstimationFailed", "Deployment %s: %v",
+				client.ObjectKeyFromObject(deployment), err)
+		}
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionCostEstimated, metav1.ConditionFalse,
+			"EstimationFailed", err.Error())
+	case cost == nil:
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionCostEstimated, metav1.ConditionFalse,
+			"NoPricingProfile", fmt.Sprintf("No PricingProfile named %s exists", optimizationv1.DefaultPricingProfile))
+	default:
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionCostEstimated, metav1.ConditionTrue,
+			"Estimated", fmt.Sprintf("Costs estimated with PricingProfile %s", cost.PricingProfile))
+	}
+}
+
+// estimateCost prices the current and the recommended requests of all
+// replicas of the deployment with the PricingProfile of the policy. It
+// returns nil without error when the policy names no profile and the
+// default profile does not exist. Resources the recommendation leaves
+// unmanaged are priced at their current requests on both sides.
+func (r *ResourceOptimizerReconciler) estimateCost(ctx context.Context, policy optimizationv1.Policy, deployment *appsv1.Deployment, recommendation *optimizationv1.ResourceRecommendation) (*optimizationv1.CostEstimate, error) {
+	name := policy.PricingProfile
+	if name == "" {
+		name = optimizationv1.DefaultPricingProfile
+	}
+	profile := &optimizationv1.PricingProfile{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, profile); err != nil {
+		if client.IgnoreNotFound(err) == nil && policy.PricingProfile == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get PricingProfile %s: %w", name, err)
+	}
+
+	rates, pool, err := r.nodePoolRates(ctx, profile, deployment)
+	if err != nil {
+		return nil, err
+	}
+	cpuRate, err := strconv.ParseFloat(rates.CPUHourly, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cpu rate in PricingProfile %s: %w", name, err)
+	}
+	memoryRate, err := strconv.ParseFloat(rates.MemoryGiBHourly, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory rate in PricingProfile %s: %w", name, err)
+	}
+
+	current := managedRequests(deployment)
+	recommended := current.DeepCopy()
+	if recommendation.CPU.Request != "" {
+		quantity, err := resource.ParseQuantity(recommendation.CPU.Request)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu request %q: %w", recommendation.CPU.Request, err)
+		}
+		recommended[corev1.ResourceCPU] = quantity
+	}
+	if recommendation.Memory.Request != "" {
+		quantity, err := resource.ParseQuantity(recommendation.Memory.Request)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory request %q: %w", recommendation.Memory.Request, err)
+		}
+		recommended[corev1.ResourceMemory] = quantity
+	}
+
+	replicas := 1.0
+	if deployment.Spec.Replicas != nil {
+		replicas = float64(*deployment.Spec.Replicas)
+	}
+	monthly := func(requests corev1.ResourceList) float64 {
+		cores := requests.Cpu().AsApproximateFloat64()
+		gib := requests.Memory().AsApproximateFloat64() / (1 << 30)
+		return (cores*cpuRate + gib*memoryRate) * replicas * hoursPerMonth
+	}
+	currentCost, recommendedCost := monthly(current), monthly(recommended)
+
+	currency := profile.Spec.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	return &optimizationv1.CostEstimate{
+		PricingProfile:     name,
+		NodePool:           pool,
+		Currency:           currency,
+		CurrentMonthly:     strconv.FormatFloat(currentCost, 'f', 2, 64),
+		RecommendedMonthly: strconv.FormatFloat(recommendedCost, 'f', 2, 64),
+		MonthlySavings:     strconv.FormatFloat(currentCost-recommendedCost, 'f', 2, 64),
+	}, nil
+}
+
+// nodePoolRates returns the rates of the first node pool of the profile that
+// matches the node the workload runs on, together with the pool name, or the
+// default rates of the profile. The node of the first scheduled pod, by name,
+// decides, so the choice is stable across reconciles.
+func (r *ResourceOptimizerReconciler) nodePoolRates(ctx context.Context, profile *optimizationv1.PricingProfile, deployment *appsv1.Deployment) (optimizationv1.ResourceRates, string, error) {
+	if len(profile.Spec.NodePools) == 0 || deployment.Spec.Selector == nil {
+		return profile.Spec.Rates, "", nil
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return optimizationv1.ResourceRates{}, "", err
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(deployment.Namespace),
+		client.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+		return optimizationv1.ResourceRates{}, "", fmt.Errorf("failed to list pods: %w", err)
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	var nodeName string
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			nodeName = pod.Spec.NodeName
+			break
+		}
+	}
+	if nodeName == "" {
+		return profile.Spec.Rates, "", nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return profile.Spec.Rates, "", nil
+		}
+		return optimizationv1.ResourceRates{}, "", fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	for _, pool := range profile.Spec.NodePools {
+		selector, err := metav1.LabelSelectorAsSelector(&pool.NodeSelector)
+		if err != nil {
+			return optimizationv1.ResourceRates{}, "", fmt.Errorf("invalid node selector of node pool %s: %w", pool.Name, err)
+		}
+		if selector.Matches(labels.Set(node.Labels)) {
+			return pool.Rates, pool.Name, nil
+		}
+	}
+	return profile.Spec.Rates, "", nil
+}
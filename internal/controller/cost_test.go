@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Cost estimation", func() {
+	var (
+		ctx            context.Context
+		deployment     *appsv1.Deployment
+		profile        *optimizationv1.PricingProfile
+		recommendation *optimizationv1.ResourceRecommendation
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		deployment = testDeployment(2, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		})
+		profile = &optimizationv1.PricingProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: optimizationv1.DefaultPricingProfile},
+			Spec: optimizationv1.PricingProfileSpec{
+				Currency: "EUR",
+				Rates:    optimizationv1.ResourceRates{CPUHourly: "0.04", MemoryGiBHourly: "0.005"},
+				NodePools: []optimizationv1.NodePoolPricing{{
+					Name: "spot",
+					NodeSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"node.kubernetes.io/lifecycle": "spot"},
+					},
+					Rates: optimizationv1.ResourceRates{CPUHourly: "0.01", MemoryGiBHourly: "0.001"},
+				}},
+			},
+		}
+		recommendation = &optimizationv1.ResourceRecommendation{
+			CPU:    optimizationv1.CPURecommendation{Request: "500m", Limit: "1"},
+			Memory: optimizationv1.MemoryRecommendation{Request: "1Gi", Limit: "1Gi"},
+		}
+	})
+
+	It("Should price all replicas with the default rates", func() {
+		cost, err := newTestReconciler(profile).estimateCost(ctx, optimizationv1.Policy{}, deployment, recommendation)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*cost).To(Equal(optimizationv1.CostEstimate{
+			PricingProfile:     optimizationv1.DefaultPricingProfile,
+			Currency:           "EUR",
+			CurrentMonthly:     "73.00",
+			RecommendedMonthly: "36.50",
+			MonthlySavings:     "36.50",
+		}))
+	})
+
+	It("Should use the rates of the node pool the pods run on", func() {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"node.kubernetes.io/lifecycle": "spot"},
+		}}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-service-abc", Namespace: "production", Labels: map[string]string{"app": "api-service"}},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		}
+
+		cost, err := newTestReconciler(profile, node, pod).estimateCost(ctx, optimizationv1.Policy{}, deployment, recommendation)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cost.NodePool).To(Equal("spot"))
+		Expect(cost.CurrentMonthly).To(Equal("17.52"))
+		Expect(cost.MonthlySavings).To(Equal("8.76"))
+	})
+
+	It("Should keep unmanaged resources at their current cost", func() {
+		recommendation.Memory = optimizationv1.MemoryRecommendation{}
+
+		cost, err := newTestReconciler(profile).estimateCost(ctx, optimizationv1.Policy{}, deployment, recommendation)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cost.MonthlySavings).To(Equal("29.20"))
+	})
+
+	It("Should only fail when a referenced profile is missing", func() {
+		cost, err := newTestReconciler().estimateCost(ctx, optimizationv1.Policy{}, deployment, recommendation)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cost).To(BeNil())
+
+		_, err = newTestReconciler().estimateCost(ctx, optimizationv1.Policy{PricingProfile: "on-demand"}, deployment, recommendation)
+		Expect(err).To(MatchError(ContainSubstring("PricingProfile on-demand")))
+	})
+
+	Context("When updating the estimate of a target", func() {
+		var (
+			owner  *optimizationv1.ResourceOptimizer
+			target *optimizationv1.TargetStatus
+		)
+
+		BeforeEach(func() {
+			owner = &optimizationv1.ResourceOptimizer{ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "production"}}
+			target = &optimizationv1.TargetStatus{}
+		})
+
+		It("Should report the profile the cost was estimated with", func() {
+			newTestReconciler(profile).updateCostEstimate(ctx, owner, optimizationv1.Policy{}, deployment, recommendation, target)
+
+			Expect(recommendation.Cost).NotTo(BeNil())
+			condition := meta.FindStatusCondition(target.Conditions, conditionCostEstimated)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("Should leave the cost unset and warn once when estimation fails", func() {
+			r := newTestReconciler()
+			recorder := r.recorder.(*record.FakeRecorder)
+			policy := optimizationv1.Policy{PricingProfile: "on-demand"}
+
+			r.updateCostEstimate(ctx, owner, policy, deployment, recommendation, target)
+			Expect(recommendation.Cost).To(BeNil())
+			condition := meta.FindStatusCondition(target.Conditions, conditionCostEstimated)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("EstimationFailed"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("PricingProfile on-demand")))
+
+			r.updateCostEstimate(ctx, owner, policy, deployment, recommendation, target)
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	It("Should still publish the recommendation when estimation fails", func() {
+		r := newTestReconciler(deployment)
+		r.metricsCollector = newTestCollector(
+			testPodMetrics("api-service-a", "300m", "400Mi"),
+			testPodMetrics("api-service-b", "350m", "420Mi"),
+		)
+		owner := &optimizationv1.ResourceOptimizer{ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "production"}}
+		policy := optimizationv1.Policy{
+			Cpu:            optimizationv1.CPUPolicy{Min: "50m", Max: "2", TargetUtilization: 70},
+			PricingProfile: "on-demand",
+		}
+		target := &optimizationv1.TargetStatus{}
+
+		Expect(r.analyzeAndOptimize(ctx, owner, policy, deployment, target)).To(Succeed())
+		Expect(target.Recommendation).NotTo(BeNil())
+		Expect(target.Recommendation.Cost).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(target.Conditions, "OptimizationReady")).To(BeTrue())
+		Expect(meta.FindStatusCondition(target.Conditions, conditionCostEstimated).Reason).To(Equal("EstimationFailed"))
+	})
+})
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/metrics"
+)
+
+// testDeployment returns the "api-service" Deployment in namespace
+// "production" whose single "api" container requests the given resources.
+func testDeployment(replicas int32, requests corev1.ResourceList) *appsv1.Deployment {
+	labels := map[string]string{"app": "api-service"}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-service", Namespace: "production"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:      "api",
+						Resources: corev1.ResourceRequirements{Requests: requests},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// newTestClientBuilder returns a fake client builder holding objects, with
+// the status subresource of the types the reconcilers write status for.
+func newTestClientBuilder(objects ...client.Object) *fake.ClientBuilder {
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).
+		WithStatusSubresource(&optimizationv1.ResourceOptimizer{}, &optimizationv1.ClusterResourceOptimizer{},
+			&optimizationv1.OptimizationReport{})
+}
+
+// newTestReconciler returns a ResourceOptimizerReconciler on a fake client
+// holding objects. Its events are recorded on a record.FakeRecorder and no
+// pod metrics are reported until metricsCollector is replaced.
+func newTestReconciler(objects ...client.Object) *ResourceOptimizerReconciler {
+	return &ResourceOptimizerReconciler{
+		Client:           newTestClientBuilder(objects...).Build(),
+		Scheme:           scheme.Scheme,
+		recorder:         record.NewFakeRecorder(20),
+		metricsCollector: newTestCollector(),
+		analyzer:         metrics.NewAnalyzer(),
+	}
+}
+
+// newTestCollector returns a Collector whose metrics API reports pods.
+func newTestCollector(pods ...metricsv1beta1.PodMetrics) *metrics.Collector {
+	// The fake tracker files PodMetrics under a guessed resource name, so
+	// serve the list directly.
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsClient.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &metricsv1beta1.PodMetricsList{Items: pods}, nil
+	})
+	return metrics.NewCollector(nil, metricsClient)
+}
+
+// testPodMetrics returns the usage of a pod of the "api-service" Deployment.
+func testPodMetrics(name, cpu, memory string) metricsv1beta1.PodMetrics {
+	return metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production", Labels: map[string]string{"app": "api-service"}},
+		Containers: []metricsv1beta1.ContainerMetrics{{
+			Name: "api",
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		}},
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// defaultHistoryLimit matches the API default for spec.historyLimit.
+const defaultHistoryLimit = 10
+
+// maxTargetsSize bounds the serialized size of status.targets. etcd rejects
+// objects above 1.5MiB, and an optimizer selecting every Deployment of a
+// large cluster would get there with its full history and explanations.
+const maxTargetsSize = 1 << 20
+
+// historyLimit returns the configured history size, or the default when unset.
+func historyLimit(limit *int32) int {
+	if limit == nil {
+		return defaultHistoryLimit
+	}
+	return int(*limit)
+}
+
+// recordHistory adds the current recommendation of target to the front of its
+// history when the recommended values differ from the newest entry, so
+// periodic re-analysis without drift does not push older entries out. An
+// unchanged recommendation that got applied since marks the newest entry as
+// applied. The history is trimmed to limit entries.
+func recordHistory(target *optimizationv1.TargetStatus, targetGeneration int64, applied bool, limit int) {
+	if limit <= 0 {
+		target.History = nil
+		return
+	}
+
+	recommendation := target.Recommendation
+	if recommendation != nil {
+		if len(target.History) > 0 && target.History[0].CPU == recommendation.CPU &&
+			target.History[0].Memory == recommendation.Memory {
+			target.History[0].Applied = target.History[0].Applied || applied
+		} else {
+			record := optimizationv1.RecommendationRecord{
+				CPU:              recommendation.CPU,
+				Memory:           recommendation.Memory,
+				Confidence:       recommendation.Confidence,
+				Reason:           recommendation.Reason,
+				GeneratedAt:      recommendation.GeneratedAt,
+				TargetGeneration: targetGeneration,
+				Applied:          applied,
+			}
+			target.History = append([]optimizationv1.RecommendationRecord{record}, target.History...)
+		}
+	}
+
+	if len(target.History) > limit {
+		target.History = target.History[:limit]
+	}
+}
+
+// trimTargets sheds detail from targets until they serialize to at most limit
+// bytes: first all but the newest history entry, then the explanations of the
+// recommendations, then the history altogether. The recommendations and
+// conditions themselves are always kept, as they are acted upon. It returns
+// what was dropped, or "" if the targets fit.
+func trimTargets(targets []optimizationv1.TargetStatus, limit int) string {
+	fits := func() bool {
+		data, err := json.Marshal(targets)
+		return err != nil || len(data) <= limit
+	}
+	if fits() {
+		return ""
+	}
+
+	for i := range targets {
+		if len(targets[i].History) > 1 {
+			targets[i].History = targets[i].History[:1]
+		}
+	}
+	if fits() {
+		return "older history entries"
+	}
+
+	for i := range targets {
+		if targets[i].Recommendation != nil {
+			targets[i].Recommendation.Explanation = nil
+		}
+	}
+	if fits() {
+		return "older history entries and explanations"
+	}
+
+	for i := range targets {
+		targets[i].History = nil
+	}
+	return "history and explanations"
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Recommendation history", func() {
+	var target *optimizationv1.TargetStatus
+
+	recommend := func(cpu string) {
+		target.Recommendation = &optimizationv1.ResourceRecommendation{
+			CPU:    optimizationv1.CPURecommendation{Request: cpu, Limit: cpu},
+			Memory: optimizationv1.MemoryRecommendation{Request: "128Mi", Limit: "256Mi"},
+		}
+	}
+
+	BeforeEach(func() {
+		target = &optimizationv1.TargetStatus{}
+	})
+
+	It("Should only add an entry when the recommended values change", func() {
+		recommend("100m")
+		recordHistory(target, 1, false, 10)
+		recordHistory(target, 1, false, 10)
+		recommend("200m")
+		recordHistory(target, 2, false, 10)
+
+		Expect(target.History).To(HaveLen(2))
+		Expect(target.History[0].CPU.Request).To(Equal("200m"))
+		Expect(target.History[0].TargetGeneration).To(Equal(int64(2)))
+		Expect(target.History[1].CPU.Request).To(Equal("100m"))
+	})
+
+	It("Should mark an unchanged recommendation as applied once it is", func() {
+		recommend("100m")
+		recordHistory(target, 1, false, 10)
+		recordHistory(target, 1, true, 10)
+
+		Expect(target.History).To(HaveLen(1))
+		Expect(target.History[0].Applied).To(BeTrue())
+	})
+
+	It("Should keep the newest entries up to the limit", func() {
+		for _, cpu := range []string{"100m", "200m", "300m"} {
+			recommend(cpu)
+			recordHistory(target, 1, false, 2)
+		}
+
+		Expect(target.History).To(HaveLen(2))
+		Expect(target.History[0].CPU.Request).To(Equal("300m"))
+		Expect(target.History[1].CPU.Request).To(Equal("200m"))
+
+		recordHistory(target, 1, false, 0)
+		Expect(target.History).To(BeEmpty())
+	})
+
+	Context("When the targets exceed the status size limit", func() {
+		var targets []optimizationv1.TargetStatus
+
+		BeforeEach(func() {
+			targets = make([]optimizationv1.TargetStatus, 3)
+			for i := range targets {
+				target = &targets[i]
+				for _, cpu := range []string{"100m", "200m", "300m"} {
+					recommend(cpu)
+					target.Recommendation.Explanation = &optimizationv1.RecommendationExplanation{Samples: 100}
+					recordHistory(target, 1, false, 10)
+				}
+			}
+		})
+
+		size := func() int {
+			data, err := json.Marshal(targets)
+			Expect(err).NotTo(HaveOccurred())
+			return len(data)
+		}
+
+		It("Should leave targets within the limit alone", func() {
+			Expect(trimTargets(targets, size())).To(BeEmpty())
+			Expect(targets[0].History).To(HaveLen(3))
+		})
+
+		It("Should drop older history entries first, then explanations", func() {
+			limit := size() - 1
+			Expect(trimTargets(targets, limit)).To(Equal("older history entries"))
+			Expect(targets[0].History).To(HaveLen(1))
+			Expect(targets[0].Recommendation.Explanation).NotTo(BeNil())
+
+			limit = size() - 1
+			Expect(trimTargets(targets, limit)).To(Equal("older history entries and explanations"))
+			Expect(targets[0].History).To(HaveLen(1))
+			Expect(targets[0].Recommendation.Explanation).To(BeNil())
+			Expect(size()).To(BeNumerically("<=", limit))
+		})
+
+		It("Should always keep the recommendations", func() {
+			Expect(trimTargets(targets, 1)).To(Equal("history and explanations"))
+			for _, target := range targets {
+				Expect(target.History).To(BeEmpty())
+				Expect(target.Recommendation.CPU.Request).To(Equal("300m"))
+			}
+		})
+	})
+})
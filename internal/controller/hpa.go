@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultHPACPUUtilization is what autoscaling/v2 assumes when an HPA declares no metrics.
+const defaultHPACPUUtilization = 80
+
+// hpaConflict describes an HPA that scales the target on the utilization of a
+// resource the operator would otherwise resize. Utilization is measured against
+// requests, so changing them silently shifts the HPA's scaling behavior.
+type hpaConflict struct {
+	hpaName           string
+	resource          corev1.ResourceName
+	targetUtilization int32
+}
+
+func (c hpaConflict) String() string {
+	return fmt.Sprintf("HPA %s scales on %s utilization (target %d%%)", c.hpaName, c.resource, c.targetUtilization)
+}
+
+// findHPAConflicts returns the CPU and memory utilization metrics of HPAs that
+// scale the given deployment. Absolute (AverageValue) targets do not depend on
+// requests and are not reported. HPAs scaling a Deployment of another API
+// group, e.g. a custom resource named Deployment, are ignored.
+func (r *ResourceOptimizerReconciler) findHPAConflicts(ctx context.Context, deployment *appsv1.Deployment) ([]hpaConflict, error) {
+	hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpas, client.InNamespace(deployment.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HorizontalPodAutoscalers: %w", err)
+	}
+
+	var conflicts []hpaConflict
+	for _, hpa := range hpas.Items {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind != "Deployment" || ref.Name != deployment.Name {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != appsv1.GroupName {
+			continue
+		}
+
+		if len(hpa.Spec.Metrics) == 0 {
+			conflicts = append(conflicts, hpaConflict{
+				hpaName:           hpa.Name,
+				resource:          corev1.ResourceCPU,
+				targetUtilization: defaultHPACPUUtilization,
+			})
+			continue
+		}
+
+		for _, metric := range hpa.Spec.Metrics {
+			var name corev1.ResourceName
+			var target autoscalingv2.MetricTarget
+			switch {
+			case metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil:
+				name, target = metric.Resource.Name, metric.Resource.Target
+			case metric.Type == autoscalingv2.ContainerResourceMetricSourceType && metric.ContainerResource != nil:
+				name, target = metric.ContainerResource.Name, metric.ContainerResource.Target
+			default:
+				continue
+			}
+
+			if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+				continue
+			}
+			if target.Type != autoscalingv2.UtilizationMetricType || target.AverageUtilization == nil {
+				continue
+			}
+			conflicts = append(conflicts, hpaConflict{
+				hpaName:           hpa.Name,
+				resource:          name,
+				targetUtilization: *target.AverageUtilization,
+			})
+		}
+	}
+
+	return conflicts, nil
+}
+
+// describeHPAConflicts joins the conflicts into a single status message.
+func describeHPAConflicts(conflicts []hpaConflict) string {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		descriptions = append(descriptions, conflict.String())
+	}
+	return strings.Join(descriptions, "; ")
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("HPA conflicts", func() {
+	var (
+		ctx        context.Context
+		deployment *appsv1.Deployment
+	)
+
+	hpa := func(name, apiVersion, targetName string, metrics ...autoscalingv2.MetricSpec) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: apiVersion, Kind: "Deployment", Name: targetName},
+				MaxReplicas:    10,
+				Metrics:        metrics,
+			},
+		}
+	}
+	utilization := func(name corev1.ResourceName, percent int32) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   name,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To(percent)},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		deployment = testDeployment(2, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		})
+	})
+
+	Context("When finding conflicts", func() {
+		It("Should report utilization targets of HPAs scaling the deployment", func() {
+			averageValue := autoscalingv2.MetricSpec{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   corev1.ResourceMemory,
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: ptr.To(resource.MustParse("1Gi"))},
+				},
+			}
+			containerMemory := autoscalingv2.MetricSpec{
+				Type: autoscalingv2.ContainerResourceMetricSourceType,
+				ContainerResource: &autoscalingv2.ContainerResourceMetricSource{
+					Name:      corev1.ResourceMemory,
+					Container: "api",
+					Target:    autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To(int32(75))},
+				},
+			}
+			r := newTestReconciler(
+				hpa("cpu", "apps/v1", "api-service", utilization(corev1.ResourceCPU, 60), averageValue),
+				hpa("container", "apps/v1", "api-service", containerMemory),
+				hpa("defaults", "apps/v1", "api-service"),
+				hpa("other-deployment", "apps/v1", "worker", utilization(corev1.ResourceCPU, 60)),
+				hpa("other-group", "example.com/v1", "api-service", utilization(corev1.ResourceCPU, 60)),
+			)
+
+			conflicts, err := r.findHPAConflicts(ctx, deployment)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(ConsistOf(
+				hpaConflict{hpaName: "cpu", resource: corev1.ResourceCPU, targetUtilization: 60},
+				hpaConflict{hpaName: "container", resource: corev1.ResourceMemory, targetUtilization: 75},
+				hpaConflict{hpaName: "defaults", resource: corev1.ResourceCPU, targetUtilization: defaultHPACPUUtilization},
+			))
+		})
+
+		It("Should describe all conflicts in one message", func() {
+			Expect(describeHPAConflicts([]hpaConflict{
+				{hpaName: "api", resource: corev1.ResourceCPU, targetUtilization: 60},
+				{hpaName: "api", resource: corev1.ResourceMemory, targetUtilization: 75},
+			})).To(Equal("HPA api scales on cpu utilization (target 60%); HPA api scales on memory utilization (target 75%)"))
+		})
+	})
+
+	Context("When analyzing a deployment scaled on CPU utilization", func() {
+		var (
+			owner  *optimizationv1.ResourceOptimizer
+			policy optimizationv1.Policy
+			target *optimizationv1.TargetStatus
+			r      *ResourceOptimizerReconciler
+		)
+
+		BeforeEach(func() {
+			owner = &optimizationv1.ResourceOptimizer{ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "production"}}
+			policy = optimizationv1.Policy{
+				Cpu:    optimizationv1.CPUPolicy{Min: "50m", Max: "2", TargetUtilization: 70},
+				Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+			}
+			target = &optimizationv1.TargetStatus{}
+			r = newTestReconciler(deployment, hpa("api", "apps/v1", "api-service", utilization(corev1.ResourceCPU, 60)))
+			r.metricsCollector = newTestCollector(
+				testPodMetrics("api-service-a", "300m", "400Mi"),
+				testPodMetrics("api-service-b", "350m", "420Mi"),
+			)
+		})
+
+		It("Should withhold the recommendation and warn once when refusing", func() {
+			policy.HPAConflict = optimizationv1.HPAConflictRefuse
+			recorder := r.recorder.(*record.FakeRecorder)
+
+			Expect(r.analyzeAndOptimize(ctx, owner, policy, deployment, target)).To(Succeed())
+			Expect(target.Recommendation).To(BeNil())
+			conflict := meta.FindStatusCondition(target.Conditions, "ConflictsWithHPA")
+			Expect(conflict.Status).To(Equal(metav1.ConditionTrue))
+			Expect(conflict.Reason).To(Equal("Refused"))
+			Expect(meta.FindStatusCondition(target.Conditions, "OptimizationReady").Reason).To(Equal("ConflictsWithHPA"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning ConflictsWithHPA")))
+
+			Expect(r.analyzeAndOptimize(ctx, owner, policy, deployment, target)).To(Succeed())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("Should leave CPU unmanaged when skipping conflicting resources", func() {
+			policy.HPAConflict = optimizationv1.HPAConflictSkipConflicting
+
+			Expect(r.analyzeAndOptimize(ctx, owner, policy, deployment, target)).To(Succeed())
+			Expect(meta.FindStatusCondition(target.Conditions, "ConflictsWithHPA").Reason).To(Equal("ResourcesSkipped"))
+			Expect(target.Recommendation).NotTo(BeNil())
+			Expect(target.Recommendation.CPU.Request).To(BeEmpty())
+			Expect(target.Recommendation.Explanation.CPU.Unmanaged).To(Equal("HPA"))
+			Expect(target.Recommendation.Memory.Request).NotTo(BeEmpty())
+		})
+
+		It("Should size CPU for the HPA target when aligning to it", func() {
+			policy.HPAConflict = optimizationv1.HPAConflictAlignTarget
+
+			Expect(r.analyzeAndOptimize(ctx, owner, policy, deployment, target)).To(Succeed())
+			Expect(meta.FindStatusCondition(target.Conditions, "ConflictsWithHPA").Reason).To(Equal("TargetAligned"))
+			Expect(target.Recommendation).NotTo(BeNil())
+			Expect(target.Recommendation.CPU.Request).NotTo(BeEmpty())
+			Expect(target.Recommendation.Explanation.CPU.Policy.TargetUtilization).To(Equal(int32(60)))
+		})
+	})
+})
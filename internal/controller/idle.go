@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/metrics"
+)
+
+const (
+	conditionIdle = "Idle"
+
+	// Defaults matching the API defaults of spec.policy.idle
+	defaultIdlePeriod       = 24 * time.Hour
+	defaultIdleCPUThreshold = "5m"
+)
+
+// idleSettings returns the detection period and CPU threshold of the policy,
+// falling back to the defaults. A zero period disables detection.
+func idleSettings(policy *optimizationv1.IdlePolicy) (time.Duration, resource.Quantity, error) {
+	period, threshold := defaultIdlePeriod, defaultIdleCPUThreshold
+	if policy != nil {
+		if policy.Period != nil {
+			period = policy.Period.Duration
+		}
+		if policy.CPUThreshold != "" {
+			threshold = policy.CPUThreshold
+		}
+	}
+	quantity, err := resource.ParseQuantity(threshold)
+	if err != nil {
+		return 0, resource.Quantity{}, fmt.Errorf("invalid idle cpu threshold %q: %w", threshold, err)
+	}
+	return period, quantity, nil
+}
+
+// updateIdleCondition sets the Idle condition of the target from the usage
+// history and reports whether the workload is idle. An event is recorded on
+// owner when the workload becomes idle.
+func (r *ResourceOptimizerReconciler) updateIdleCondition(owner client.Object, policy optimizationv1.Policy, deployment *appsv1.Deployment, workloadMetrics *metrics.WorkloadMetrics, target *optimizationv1.TargetStatus) (bool, error) {
+	period, threshold, err := idleSettings(policy.Idle)
+	if err != nil {
+		return false, err
+	}
+	if period <= 0 {
+		meta.RemoveStatusCondition(&target.Conditions, conditionIdle)
+		return false, nil
+	}
+
+	result := r.analyzer.DetectIdle(workloadMetrics, period, threshold, time.Now())
+	switch {
+	case result.Idle:
+		message := fmt.Sprintf("CPU usage stayed at or below %s per pod for %s (peak %s); "+
+			"consider scaling the workload to zero or deleting it", threshold.String(), period, result.PeakCPU.String())
+		if !meta.IsStatusConditionTrue(target.Conditions, conditionIdle) {
+			r.recorder.Eventf(owner, corev1.EventTypeNormal, "WorkloadIdle", "Deployment %s: %s",
+				client.ObjectKeyFromObject(deployment), message)
+		}
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionIdle, metav1.ConditionTrue, "NoUsage", message)
+	case result.Observed < period:
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionIdle, metav1.ConditionFalse, "InsufficientHistory",
+			fmt.Sprintf("Usage observed for %s of the %s idle period", result.Observed.Truncate(time.Minute), period))
+	default:
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionIdle, metav1.ConditionFalse, "InUse",
+			fmt.Sprintf("Peak CPU usage of %s per pod over the last %s exceeds %s",
+				result.PeakCPU.String(), period, threshold.String()))
+	}
+	return result.Idle, nil
+}
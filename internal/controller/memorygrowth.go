@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/metrics"
+)
+
+const conditionMemoryGrowth = "MemoryGrowthDetected"
+
+// updateMemoryGrowthCondition sets the MemoryGrowthDetected condition of the
+// target from the usage history and reports whether memory keeps growing. A
+// warning event is recorded on owner when growth is first detected, since
+// rightsizing a leaking workload only delays its OOM kill.
+func (r *ResourceOptimizerReconciler) updateMemoryGrowthCondition(owner client.Object, policy optimizationv1.Policy, deployment *appsv1.Deployment, workloadMetrics *metrics.WorkloadMetrics, target *optimizationv1.TargetStatus) bool {
+	var grace time.Duration
+	if policy.StartupGracePeriod != nil {
+		grace = policy.StartupGracePeriod.Duration
+	}
+	result := r.analyzer.DetectMemoryGrowth(workloadMetrics, grace)
+	if !result.Detected {
+		setCondition(&target.Conditions, owner.GetGeneration(), conditionMemoryGrowth, metav1.ConditionFalse, "NoGrowth",
+			"Memory usage does not grow steadily between restarts")
+		return false
+	}
+
+	message := fmt.Sprintf("Memory of pod %s grew from %s to %s over %s (+%s/h) without being freed; "+
+		"this looks like a leak, so memory is left unmanaged until it is fixed",
+		result.Pod, result.From.String(), result.To.String(), result.Duration.Truncate(time.Minute), result.PerHour.String())
+	if !meta.IsStatusConditionTrue(target.Conditions, conditionMemoryGrowth) {
+		r.recorder.Eventf(owner, corev1.EventTypeWarning, "MemoryGrowthDetected", "Deployment %s: %s",
+			client.ObjectKeyFromObject(deployment), message)
+	}
+	setCondition(&target.Conditions, owner.GetGeneration(), conditionMemoryGrowth, metav1.ConditionTrue, "MonotonicGrowth", message)
+	return true
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// Operator metrics are served on the manager's metrics endpoint.
+var (
+	statusUpdateFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cost_optimizer_status_update_failures_total",
+		Help: "Status writes that failed, by optimizer kind.",
+	}, []string{"kind"})
+
+	metricsAPIErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cost_optimizer_metrics_api_errors_total",
+		Help: "Failed reads of workload usage from the metrics API, by workload.",
+	}, []string{"namespace", "deployment"})
+
+	patchesPublishedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cost_optimizer_patches_published_total",
+		Help: "Recommendation patches created or changed in a ConfigMap, by optimizer.",
+	}, []string{"optimizer"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(statusUpdateFailuresTotal, metricsAPIErrorsTotal, patchesPublishedTotal)
+}
+
+// Labels of the per-target series exported by optimizerCollector.
+var targetLabels = []string{"optimizer_kind", "optimizer", "namespace", "deployment", "resource"}
+
+var (
+	currentRequestsDesc = prometheus.NewDesc("cost_optimizer_current_requests",
+		"Requests per pod of the managed containers of the target, in cores or bytes.", targetLabels, nil)
+	recommendedRequestsDesc = prometheus.NewDesc("cost_optimizer_recommended_requests",
+		"Recommended requests per pod of the target, in cores or bytes.", targetLabels, nil)
+	estimatedSavingsDesc = prometheus.NewDesc("cost_optimizer_estimated_savings",
+		"Requests freed across all replicas of the target by the recommendation, in cores or bytes. Negative when the recommendation grows the target.",
+		targetLabels, nil)
+	confidenceDesc = prometheus.NewDesc("cost_optimizer_recommendation_confidence",
+		"Confidence of the current recommendation of the target, from 0 to 1.", targetLabels[:4], nil)
+	recommendationTimestampDesc = prometheus.NewDesc("cost_optimizer_recommendation_timestamp_seconds",
+		"Time the current recommendation of the target was generated.", targetLabels[:4], nil)
+	monthlySavingsDesc = prometheus.NewDesc("cost_optimizer_estimated_monthly_savings",
+		"Monthly cost saved by applying the recommendation of the target, from its PricingProfile.",
+		append(targetLabels[:4:4], "currency"), nil)
+	optimizerReadyDesc = prometheus.NewDesc("cost_optimizer_ready",
+		"Whether the optimizer reports the Ready condition for its current generation.", targetLabels[:2], nil)
+)
+
+// optimizerCollector exports the recommendations of all optimizers at scrape
+// time from the manager cache, so series disappear together with the
+// optimizers and targets they describe.
+type optimizerCollector struct {
+	client client.Reader
+}
+
+var _ prometheus.Collector = &optimizerCollector{}
+
+// registerOptimizerCollector registers the collector once per process.
+func registerOptimizerCollector(c client.Reader) error {
+	err := ctrlmetrics.Registry.Register(&optimizerCollector{client: c})
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
+
+// Describe implements prometheus.Collector.
+func (c *optimizerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- currentRequestsDesc
+	ch <- recommendedRequestsDesc
+	ch <- estimatedSavingsDesc
+	ch <- confidenceDesc
+	ch <- recommendationTimestampDesc
+	ch <- monthlySavingsDesc
+	ch <- optimizerReadyDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *optimizerCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	log := logf.Log.WithName("metrics")
+
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := c.client.List(ctx, optimizers); err != nil {
+		log.Error(err, "Failed to list ResourceOptimizers")
+	}
+	for i := range optimizers.Items {
+		optimizer := &optimizers.Items[i]
+		name := client.ObjectKeyFromObject(optimizer).String()
+		c.collectReady(ch, "ResourceOptimizer", name, optimizer.Status.Conditions, optimizer.Generation)
+		// Only the status of the current target mode is exported, as the other
+		// may still be set until the next reconcile and name the same workload
+		if optimizer.Spec.TargetSelector != nil {
+			for _, target := range optimizer.Status.Targets {
+				if target.Recommendation != nil {
+					c.collectTarget(ctx, ch, "ResourceOptimizer", name, target.TargetRef, target.Recommendation)
+				}
+			}
+		} else if ref := optimizer.Spec.TargetRef; ref != nil && optimizer.Status.CurrentRecommendation != nil {
+			c.collectTarget(ctx, ch, "ResourceOptimizer", name, *ref, optimizer.Status.CurrentRecommendation)
+		}
+	}
+
+	clusterOptimizers := &optimizationv1.ClusterResourceOptimizerList{}
+	if err := c.client.List(ctx, clusterOptimizers); err != nil {
+		log.Error(err, "Failed to list ClusterResourceOptimizers")
+	}
+	for i := range clusterOptimizers.Items {
+		optimizer := &clusterOptimizers.Items[i]
+		c.collectReady(ch, "ClusterResourceOptimizer", optimizer.Name, optimizer.Status.Conditions, optimizer.Generation)
+		for _, target := range optimizer.Status.Targets {
+			if target.Recommendation != nil {
+				c.collectTarget(ctx, ch, "ClusterResourceOptimizer", optimizer.Name, target.TargetRef, target.Recommendation)
+			}
+		}
+	}
+}
+
+func (c *optimizerCollector) collectReady(ch chan<- prometheus.Metric, kind, name string, conditions []metav1.Condition, generation int64) {
+	ready := 0.0
+	if condition := meta.FindStatusCondition(conditions, conditionReady); condition != nil &&
+		condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == generation {
+		ready = 1
+	}
+	ch <- prometheus.MustNewConstMetric(optimizerReadyDesc, prometheus.GaugeValue, ready, kind, name)
+}
+
+// collectTarget exports the recommendation for one workload. Requests are
+// compared per pod over the managed containers, savings over all replicas.
+func (c *optimizerCollector) collectTarget(ctx context.Context, ch chan<- prometheus.Metric, kind, name string, ref optimizationv1.TargetRef, recommendation *optimizationv1.ResourceRecommendation) {
+	ch <- prometheus.MustNewConstMetric(confidenceDesc, prometheus.GaugeValue,
+		float64(recommendation.Confidence)/100, kind, name, ref.Namespace, ref.Name)
+	ch <- prometheus.MustNewConstMetric(recommendationTimestampDesc, prometheus.GaugeValue,
+		float64(recommendation.GeneratedAt.Unix()), kind, name, ref.Namespace, ref.Name)
+	if cost := recommendation.Cost; cost != nil {
+		if savings, err := strconv.ParseFloat(cost.MonthlySavings, 64); err == nil {
+			ch <- prometheus.MustNewConstMetric(monthlySavingsDesc, prometheus.GaugeValue, savings,
+				kind, name, ref.Namespace, ref.Name, cost.Currency)
+		}
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, deployment); err != nil {
+		return
+	}
+	current := managedRequests(deployment)
+	replicas := float64(recommendation.CurrentReplicas)
+	if deployment.Spec.Replicas != nil {
+		replicas = float64(*deployment.Spec.Replicas)
+	}
+
+	for resourceName, value := range map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    recommendation.CPU.Request,
+		corev1.ResourceMemory: recommendation.Memory.Request,
+	} {
+		if value == "" {
+			// Unmanaged resource, e.g. pinned or scaled by an HPA
+			continue
+		}
+		recommended, err := resource.ParseQuantity(value)
+		if err != nil {
+			continue
+		}
+		labels := []string{kind, name, ref.Namespace, ref.Name, string(resourceName)}
+		ch <- prometheus.MustNewConstMetric(recommendedRequestsDesc, prometheus.GaugeValue, recommended.AsApproximateFloat64(), labels...)
+
+		currentValue, ok := current[resourceName]
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(currentRequestsDesc, prometheus.GaugeValue, currentValue.AsApproximateFloat64(), labels...)
+		ch <- prometheus.MustNewConstMetric(estimatedSavingsDesc, prometheus.GaugeValue,
+			(currentValue.AsApproximateFloat64()-recommended.AsApproximateFloat64())*replicas, labels...)
+	}
+}
+
+// managedRequests sums the requests of the managed containers in the pod template.
+func managedRequests(deployment *appsv1.Deployment) corev1.ResourceList {
+	return managedResources(deployment, func(resources corev1.ResourceRequirements) corev1.ResourceList {
+		return resources.Requests
+	})
+}
+
+// managedLimits sums the limits of the managed containers in the pod template.
+func managedLimits(deployment *appsv1.Deployment) corev1.ResourceList {
+	return managedResources(deployment, func(resources corev1.ResourceRequirements) corev1.ResourceList {
+		return resources.Limits
+	})
+}
+
+// managedResources sums the resource lists selected by list over the managed
+// containers in the pod template.
+func managedResources(deployment *appsv1.Deployment, list func(corev1.ResourceRequirements) corev1.ResourceList) corev1.ResourceList {
+	totals := corev1.ResourceList{}
+	containers := deployment.Spec.Template.Spec.Containers
+	for _, name := range optimizationv1.ManagedContainers(deployment.Annotations, containers) {
+		for _, container := range containers {
+			if container.Name != name {
+				continue
+			}
+			for resourceName, quantity := range list(container.Resources) {
+				total := totals[resourceName]
+				total.Add(quantity)
+				totals[resourceName] = total
+			}
+		}
+	}
+	return totals
+}
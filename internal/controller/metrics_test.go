@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Optimizer metrics", func() {
+	It("Should export current and recommended requests and savings per target", func() {
+		deployment := testDeployment(2, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		})
+		optimizer := &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "production", Generation: 2},
+			Spec: optimizationv1.ResourceOptimizerSpec{
+				TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+			},
+			Status: optimizationv1.ResourceOptimizerStatus{
+				Conditions: []metav1.Condition{{
+					Type: conditionReady, Status: metav1.ConditionTrue, Reason: "RecommendationReady", ObservedGeneration: 2,
+				}},
+				CurrentRecommendation: &optimizationv1.ResourceRecommendation{
+					CPU:         optimizationv1.CPURecommendation{Request: "250m", Limit: "500m"},
+					Confidence:  80,
+					GeneratedAt: metav1.Unix(1700000000, 0),
+				},
+			},
+		}
+		c := newTestClientBuilder(deployment, optimizer).Build()
+
+		expected := `
+# HELP cost_optimizer_current_requests Requests per pod of the managed containers of the target, in cores or bytes.
+# TYPE cost_optimizer_current_requests gauge
+cost_optimizer_current_requests{deployment="api-service",namespace="production",optimizer="production/api-optimizer",optimizer_kind="ResourceOptimizer",resource="cpu"} 0.5
+# HELP cost_optimizer_estimated_savings Requests freed across all replicas of the target by the recommendation, in cores or bytes. Negative when the recommendation grows the target.
+# TYPE cost_optimizer_estimated_savings gauge
+cost_optimizer_estimated_savings{deployment="api-service",namespace="production",optimizer="production/api-optimizer",optimizer_kind="ResourceOptimizer",resource="cpu"} 0.5
+# HELP cost_optimizer_ready Whether the optimizer reports the Ready condition for its current generation.
+# TYPE cost_optimizer_ready gauge
+cost_optimizer_ready{optimizer="production/api-optimizer",optimizer_kind="ResourceOptimizer"} 1
+# HELP cost_optimizer_recommendation_confidence Confidence of the current recommendation of the target, from 0 to 1.
+# TYPE cost_optimizer_recommendation_confidence gauge
+cost_optimizer_recommendation_confidence{deployment="api-service",namespace="production",optimizer="production/api-optimizer",optimizer_kind="ResourceOptimizer"} 0.8
+# HELP cost_optimizer_recommended_requests Recommended requests per pod of the target, in cores or bytes.
+# TYPE cost_optimizer_recommended_requests gauge
+cost_optimizer_recommended_requests{deployment="api-service",namespace="production",optimizer="production/api-optimizer",optimizer_kind="ResourceOptimizer",resource="cpu"} 0.25
+`
+		Expect(testutil.CollectAndCompare(&optimizerCollector{client: c}, strings.NewReader(expected),
+			"cost_optimizer_current_requests", "cost_optimizer_recommended_requests", "cost_optimizer_estimated_savings",
+			"cost_optimizer_recommendation_confidence", "cost_optimizer_ready")).To(Succeed())
+	})
+})
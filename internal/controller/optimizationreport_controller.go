@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+const (
+	// defaultReportRefreshInterval matches the API default for spec.refreshInterval.
+	defaultReportRefreshInterval = 15 * time.Minute
+
+	// unassignedTeam groups the workloads without a team label.
+	unassignedTeam = "unassigned"
+)
+
+// OptimizationReportReconciler reconciles an OptimizationReport object
+type OptimizationReportReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=optimizationreports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=optimizationreports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=optimization.stackbalancer.io,resources=optimizationreports/finalizers,verbs=update
+
+// Reconcile aggregates the current recommendations of all ResourceOptimizers
+// and ClusterResourceOptimizers per namespace and team and refreshes the
+// report periodically.
+func (r *OptimizationReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	report := &optimizationv1.OptimizationReport{}
+	if err := r.Get(ctx, req.NamespacedName, report); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(&report.Spec.NamespaceSelector)
+	if err != nil {
+		// Retrying does not help until the spec changes
+		setCondition(
+			&report.Status.Conditions,
+			report.Generation,
+			conditionReady,
+			metav1.ConditionFalse,
+			"InvalidSelector",
+			err.Error(),
+		)
+		return ctrl.Result{}, r.updateStatus(ctx, report)
+	}
+
+	workloads, err := r.collectWorkloads(ctx, report, namespaceSelector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	total := newReportAccumulator()
+	namespaces := map[string]*reportAccumulator{}
+	teams := map[string]*reportAccumulator{}
+	for _, workload := range workloads {
+		total.add(workload)
+		if namespaces[workload.target.Namespace] == nil {
+			namespaces[workload.target.Namespace] = newReportAccumulator()
+		}
+		namespaces[workload.target.Namespace].add(workload)
+		if teams[workload.team] == nil {
+			teams[workload.team] = newReportAccumulator()
+		}
+		teams[workload.team].add(workload)
+	}
+
+	now := metav1.Now()
+	report.Status.LastRefreshed = &now
+	report.Status.Total = total.summary()
+	report.Status.Namespaces = groupReports(namespaces)
+	report.Status.Teams = groupReports(teams)
+	report.Status.TopOffenders = topOffenders(workloads, topOffendersLimit(report.Spec.TopOffenders))
+	setCondition(
+		&report.Status.Conditions,
+		report.Generation,
+		conditionReady,
+		metav1.ConditionTrue,
+		"Refreshed",
+		fmt.Sprintf("%d workloads in %d namespaces", len(workloads), len(namespaces)),
+	)
+	if err := r.updateStatus(ctx, report); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Report refreshed", "workloads", len(workloads), "namespaces", len(namespaces))
+
+	interval := report.Spec.RefreshInterval.Duration
+	if interval <= 0 {
+		interval = defaultReportRefreshInterval
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// collectWorkloads returns the reports of all workloads with a recommendation
+// in the selected namespaces, sorted by namespace and name. A workload
+// recommended by several optimizers is counted once.
+func (r *OptimizationReportReconciler) collectWorkloads(ctx context.Context, report *optimizationv1.OptimizationReport, namespaceSelector labels.Selector) ([]*workloadReport, error) {
+	log := logf.FromContext(ctx)
+
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList); err != nil {
+		return nil, err
+	}
+	namespaces := make(map[string]*corev1.Namespace, len(namespaceList.Items))
+	for i := range namespaceList.Items {
+		namespaces[namespaceList.Items[i].Name] = &namespaceList.Items[i]
+	}
+
+	type recommended struct {
+		optimizer      string
+		target         optimizationv1.TargetRef
+		recommendation *optimizationv1.ResourceRecommendation
+	}
+	var candidates []recommended
+
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := r.List(ctx, optimizers); err != nil {
+		return nil, err
+	}
+	for _, optimizer := range optimizers.Items {
+		name := client.ObjectKeyFromObject(&optimizer).String()
+		if ref := optimizer.Spec.TargetRef; ref != nil && optimizer.Status.CurrentRecommendation != nil {
+			candidates = append(candidates, recommended{name, *ref, optimizer.Status.CurrentRecommendation})
+		}
+		for _, target := range optimizer.Status.Targets {
+			if target.Recommendation != nil {
+				candidates = append(candidates, recommended{name, target.TargetRef, target.Recommendation})
+			}
+		}
+	}
+	clusterOptimizers := &optimizationv1.ClusterResourceOptimizerList{}
+	if err := r.List(ctx, clusterOptimizers); err != nil {
+		return nil, err
+	}
+	for _, optimizer := range clusterOptimizers.Items {
+		for _, target := range optimizer.Status.Targets {
+			if target.Recommendation != nil {
+				candidates = append(candidates, recommended{optimizer.Name, target.TargetRef, target.Recommendation})
+			}
+		}
+	}
+
+	teamLabel := report.Spec.TeamLabel
+	if teamLabel == "" {
+		teamLabel = "team"
+	}
+	seen := map[optimizationv1.TargetRef]bool{}
+	var workloads []*workloadReport
+	for _, candidate := range candidates {
+		namespace := namespaces[candidate.target.Namespace]
+		if seen[candidate.target] || namespace == nil || !namespaceSelector.Matches(labels.Set(namespace.Labels)) {
+			continue
+		}
+		seen[candidate.target] = true
+
+		workload, err := newWorkloadReport(candidate.target, candidate.optimizer, candidate.recommendation)
+		if err != nil {
+			// Recommendations from before observed resources were recorded
+			// are included once they are regenerated
+			log.V(1).Info("Skipping recommendation", "reason", err.Error())
+			continue
+		}
+
+		workload.team = namespace.Labels[teamLabel]
+		deployment := &appsv1.Deployment{}
+		err = r.Get(ctx, client.ObjectKey{Namespace: candidate.target.Namespace, Name: candidate.target.Name}, deployment)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		if team := deployment.Labels[teamLabel]; team != "" {
+			workload.team = team
+		}
+		if workload.team == "" {
+			workload.team = unassignedTeam
+		}
+		workloads = append(workloads, workload)
+	}
+
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].target.Namespace != workloads[j].target.Namespace {
+			return workloads[i].target.Namespace < workloads[j].target.Namespace
+		}
+		return workloads[i].target.Name < workloads[j].target.Name
+	})
+	return workloads, nil
+}
+
+// updateStatus sets observedGeneration and writes the status. Failed writes
+// are surfaced as an event and metric.
+func (r *OptimizationReportReconciler) updateStatus(ctx context.Context, report *optimizationv1.OptimizationReport) error {
+	report.Status.ObservedGeneration = report.Generation
+
+	err := patchStatus(ctx, r.Client, report, func(latest *optimizationv1.OptimizationReport) {
+		latest.Status = report.Status
+	})
+	if client.IgnoreNotFound(err) != nil {
+		statusUpdateFailuresTotal.WithLabelValues("OptimizationReport").Inc()
+		r.recorder.Eventf(report, corev1.EventTypeWarning, "StatusUpdateFailed", "Failed to update status: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OptimizationReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("cost-optimizer-controller")
+
+	// Reports are refreshed on their interval rather than on every change of
+	// an optimizer, which would rebuild them on each reconcile of any optimizer.
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&optimizationv1.OptimizationReport{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("optimizationreport").
+		Complete(r)
+}
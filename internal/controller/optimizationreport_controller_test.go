@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("OptimizationReport Controller", func() {
+	It("Should aggregate recommendations per namespace and team", func() {
+		ctx := context.Background()
+		namespace := func(name string, labels map[string]string) *corev1.Namespace {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		}
+		recommendation := func(cpu, memory string, observed optimizationv1.ObservedResources, replicas int32) *optimizationv1.ResourceRecommendation {
+			return &optimizationv1.ResourceRecommendation{
+				CPU:             optimizationv1.CPURecommendation{Request: cpu},
+				Memory:          optimizationv1.MemoryRecommendation{Request: memory},
+				CurrentReplicas: replicas,
+				Observed:        &observed,
+			}
+		}
+
+		optimizer := &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "production"},
+			Spec: optimizationv1.ResourceOptimizerSpec{
+				TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+			},
+			Status: optimizationv1.ResourceOptimizerStatus{
+				CurrentRecommendation: recommendation("250m", "256Mi", optimizationv1.ObservedResources{
+					Requests: optimizationv1.ResourceAmounts{CPU: "500m", Memory: "512Mi"},
+					Usage:    optimizationv1.ResourceAmounts{CPU: "100m", Memory: "200Mi"},
+				}, 2),
+			},
+		}
+		worker := recommendation("1", "", optimizationv1.ObservedResources{
+			Requests: optimizationv1.ResourceAmounts{CPU: "2", Memory: "1Gi"},
+			Usage:    optimizationv1.ResourceAmounts{CPU: "500m", Memory: "512Mi"},
+		}, 1)
+		worker.Cost = &optimizationv1.CostEstimate{
+			PricingProfile: "default", Currency: "USD",
+			CurrentMonthly: "60.00", RecommendedMonthly: "30.00", MonthlySavings: "30.00",
+		}
+		clusterOptimizer := &optimizationv1.ClusterResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-optimizer"},
+			Status: optimizationv1.ClusterResourceOptimizerStatus{
+				Targets: []optimizationv1.TargetStatus{
+					{
+						TargetRef:      optimizationv1.TargetRef{Kind: "Deployment", Name: "worker", Namespace: "staging"},
+						Recommendation: worker,
+					},
+					{
+						TargetRef:      optimizationv1.TargetRef{Kind: "Deployment", Name: "coredns", Namespace: "kube-system"},
+						Recommendation: worker,
+					},
+				},
+			},
+		}
+		report := &optimizationv1.OptimizationReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "fleet"},
+			Spec: optimizationv1.OptimizationReportSpec{
+				NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"},
+				}}},
+				TeamLabel:       "team",
+				TopOffenders:    ptr.To[int32](10),
+				RefreshInterval: metav1.Duration{Duration: time.Hour},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(
+				namespace("production", map[string]string{"kubernetes.io/metadata.name": "production", "team": "payments"}),
+				namespace("staging", map[string]string{"kubernetes.io/metadata.name": "staging"}),
+				namespace("kube-system", map[string]string{"kubernetes.io/metadata.name": "kube-system"}),
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+					Name: "worker", Namespace: "staging", Labels: map[string]string{"team": "data"},
+				}},
+				optimizer, clusterOptimizer, report,
+			).
+			WithStatusSubresource(optimizer, clusterOptimizer, report).Build()
+		reconciler := &OptimizationReportReconciler{Client: c, Scheme: scheme.Scheme, recorder: record.NewFakeRecorder(10)}
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "fleet"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+
+		updated := &optimizationv1.OptimizationReport{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(report), updated)).To(Succeed())
+		status := updated.Status
+		Expect(meta.IsStatusConditionTrue(status.Conditions, conditionReady)).To(BeTrue())
+
+		Expect(status.Total.Workloads).To(Equal(int32(2)))
+		Expect(status.Total.Requested).To(Equal(optimizationv1.ResourceAmounts{CPU: "3", Memory: "2Gi"}))
+		Expect(status.Total.Recommended).To(Equal(optimizationv1.ResourceAmounts{CPU: "1500m", Memory: "1536Mi"}))
+		Expect(status.Total.Waste).To(Equal(optimizationv1.ResourceAmounts{CPU: "1500m", Memory: "512Mi"}))
+		Expect(status.Total.Cost).NotTo(BeNil())
+		Expect(status.Total.Cost.MonthlySavings).To(Equal("30.00"))
+
+		Expect(status.Namespaces).To(HaveLen(2))
+		Expect(status.Namespaces[0].Name).To(Equal("production"))
+		Expect(status.Teams).To(HaveLen(2))
+		Expect(status.Teams[0].Name).To(Equal("data"))
+		Expect(status.Teams[1].Name).To(Equal("payments"))
+
+		Expect(status.TopOffenders).To(HaveLen(2))
+		Expect(status.TopOffenders[0].Name).To(Equal("worker"))
+		Expect(status.TopOffenders[0].Optimizer).To(Equal("default-optimizer"))
+		Expect(status.TopOffenders[1].Name).To(Equal("api-service"))
+		Expect(status.TopOffenders[1].Waste).To(Equal(optimizationv1.ResourceAmounts{CPU: "500m", Memory: "512Mi"}))
+	})
+
+	Describe("topOffenders", func() {
+		workload := func(name, cpuWaste, currency string, savings float64) *workloadReport {
+			workload := &workloadReport{
+				target:  optimizationv1.TargetRef{Kind: "Deployment", Name: name, Namespace: "production"},
+				waste:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuWaste)},
+				savings: savings,
+			}
+			if currency != "" {
+				workload.cost = &optimizationv1.CostEstimate{Currency: currency}
+			}
+			return workload
+		}
+		names := func(offenders []optimizationv1.Offender) []string {
+			var names []string
+			for _, offender := range offenders {
+				names = append(names, offender.Name)
+			}
+			return names
+		}
+
+		It("Should rank by savings when all estimates share a currency", func() {
+			offenders := topOffenders([]*workloadReport{
+				workload("api", "2", "USD", 10),
+				workload("worker", "500m", "USD", 40),
+				workload("batch", "1", "", 0),
+			}, 10)
+			Expect(names(offenders)).To(Equal([]string{"worker", "api", "batch"}))
+		})
+
+		It("Should rank by waste when estimates use different currencies", func() {
+			offenders := topOffenders([]*workloadReport{
+				workload("api", "500m", "USD", 10),
+				workload("worker", "2", "JPY", 1500),
+				workload("batch", "1", "EUR", 9),
+			}, 2)
+			Expect(names(offenders)).To(Equal([]string{"worker", "batch"}))
+		})
+
+		It("Should list no offenders for a limit of 0 and the default when unset", func() {
+			workloads := []*workloadReport{workload("api", "2", "USD", 10)}
+			Expect(topOffenders(workloads, topOffendersLimit(ptr.To[int32](0)))).To(BeEmpty())
+			Expect(topOffenders(workloads, topOffendersLimit(nil))).To(HaveLen(1))
+			Expect(topOffendersLimit(nil)).To(Equal(defaultTopOffenders))
+		})
+	})
+})
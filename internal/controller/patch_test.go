@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/patch"
+)
+
+var _ = Describe("Patch publishing", func() {
+	var (
+		ctx        context.Context
+		optimizer  *optimizationv1.ResourceOptimizer
+		deployment *appsv1.Deployment
+		target     *optimizationv1.TargetStatus
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		optimizer = &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "production", UID: "optimizer-uid"},
+			Spec: optimizationv1.ResourceOptimizerSpec{
+				PatchOutput: &optimizationv1.PatchOutput{Format: optimizationv1.PatchFormatKustomize},
+			},
+		}
+		deployment = testDeployment(2, corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		})
+		target = &optimizationv1.TargetStatus{
+			Recommendation: &optimizationv1.ResourceRecommendation{
+				CPU:    optimizationv1.CPURecommendation{Request: "300m", Limit: "600m"},
+				Memory: optimizationv1.MemoryRecommendation{Request: "256Mi", Limit: "512Mi"},
+			},
+		}
+	})
+
+	It("Should publish the patch to a ConfigMap controlled by the optimizer", func() {
+		r := newTestReconciler(optimizer, deployment)
+		Expect(r.publishPatch(ctx, optimizer, deployment, target)).To(Succeed())
+		Expect(target.PatchConfigMap).To(Equal("api-optimizer-patch"))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, client.ObjectKey{Namespace: "production", Name: "api-optimizer-patch"}, configMap)).To(Succeed())
+		Expect(metav1.IsControlledBy(configMap, optimizer)).To(BeTrue())
+		Expect(configMap.Data).To(HaveKeyWithValue(patch.KustomizeKey, ContainSubstring("cpu: 300m")))
+	})
+
+	It("Should refuse to overwrite a ConfigMap it does not control", func() {
+		foreign := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer-patch", Namespace: "production"},
+			Data:       map[string]string{"settings.yaml": "keep: me"},
+		}
+		r := newTestReconciler(optimizer, deployment, foreign)
+
+		err := r.publishPatch(ctx, optimizer, deployment, target)
+		Expect(err).To(MatchError(errConfigMapNotOwned))
+		Expect(target.PatchConfigMap).To(BeEmpty())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(foreign.Data))
+		Expect(configMap.OwnerReferences).To(BeEmpty())
+	})
+
+	It("Should delete the ConfigMap once the patch output is removed", func() {
+		owned := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer-patch", Namespace: "production"}}
+		Expect(controllerutil.SetControllerReference(optimizer, owned, scheme.Scheme)).To(Succeed())
+		r := newTestReconciler(optimizer, deployment, owned)
+		target.PatchConfigMap = owned.Name
+		setCondition(&target.Conditions, 1, "PatchPublished", metav1.ConditionTrue, "PatchPublished", "published")
+
+		optimizer.Spec.PatchOutput = nil
+		Expect(r.unpublishPatch(ctx, optimizer, target, "patch output is disabled")).To(Succeed())
+
+		Expect(target.PatchConfigMap).To(BeEmpty())
+		Expect(meta.FindStatusCondition(target.Conditions, "PatchPublished")).To(BeNil())
+		err := r.Get(ctx, client.ObjectKeyFromObject(owned), &corev1.ConfigMap{})
+		Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should keep a ConfigMap it does not control when the patch output is removed", func() {
+		foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer-patch", Namespace: "production"}}
+		r := newTestReconciler(optimizer, deployment, foreign)
+		target.PatchConfigMap = foreign.Name
+
+		Expect(r.unpublishPatch(ctx, optimizer, target, "patch output is disabled")).To(Succeed())
+		Expect(target.PatchConfigMap).To(BeEmpty())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(foreign), &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	Context("When a target no longer gets a patch", func() {
+		// ownedPatch returns a published patch ConfigMap controlled by the optimizer
+		ownedPatch := func(name string) *corev1.ConfigMap {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production"}}
+			Expect(controllerutil.SetControllerReference(optimizer, configMap, scheme.Scheme)).To(Succeed())
+			return configMap
+		}
+		expectDeleted := func(r *ResourceOptimizerReconciler, configMap *corev1.ConfigMap) {
+			err := r.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		}
+
+		BeforeEach(func() {
+			optimizer.Spec.Policy = optimizationv1.Policy{
+				Cpu:    optimizationv1.CPUPolicy{Min: "50m", Max: "2", TargetUtilization: 70},
+				Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+			}
+			deployment.Labels = map[string]string{"app": "api-service"}
+		})
+
+		It("Should delete the patch of an excluded workload", func() {
+			published := ownedPatch("api-optimizer-patch")
+			target.PatchConfigMap = published.Name
+			deployment.Annotations = map[string]string{optimizationv1.AnnotationExclude: "true"}
+			r := newTestReconciler(optimizer, deployment, published)
+
+			Expect(r.reconcileTarget(ctx, optimizer, deployment, target)).To(Succeed())
+			Expect(target.Recommendation).To(BeNil())
+			Expect(target.PatchConfigMap).To(BeEmpty())
+			expectDeleted(r, published)
+		})
+
+		It("Should delete the patch of a workload refused for an HPA conflict", func() {
+			published := ownedPatch("api-optimizer-patch")
+			target.PatchConfigMap = published.Name
+			optimizer.Spec.Policy.HPAConflict = optimizationv1.HPAConflictRefuse
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "production"},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api-service"},
+					MaxReplicas:    10,
+					Metrics: []autoscalingv2.MetricSpec{{
+						Type: autoscalingv2.ResourceMetricSourceType,
+						Resource: &autoscalingv2.ResourceMetricSource{
+							Name:   corev1.ResourceCPU,
+							Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To[int32](60)},
+						},
+					}},
+				},
+			}
+			r := newTestReconciler(optimizer, deployment, hpa, published)
+			r.metricsCollector = newTestCollector(testPodMetrics("api-service-a", "300m", "400Mi"))
+
+			Expect(r.reconcileTarget(ctx, optimizer, deployment, target)).To(Succeed())
+			Expect(target.Recommendation).To(BeNil())
+			Expect(target.PatchConfigMap).To(BeEmpty())
+			expectDeleted(r, published)
+		})
+
+		It("Should delete the patch of a superseded or deselected workload", func() {
+			optimizer.Spec.TargetSelector = &optimizationv1.TargetSelector{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api-service"}},
+			}
+			superseded := ownedPatch("api-optimizer-api-service-patch")
+			deselected := ownedPatch("api-optimizer-worker-patch")
+			target.TargetRef = optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"}
+			target.PatchConfigMap = superseded.Name
+			optimizer.Status.Targets = []optimizationv1.TargetStatus{*target, {
+				TargetRef:      optimizationv1.TargetRef{Kind: "Deployment", Name: "worker", Namespace: "production"},
+				Recommendation: target.Recommendation.DeepCopy(),
+				PatchConfigMap: deselected.Name,
+			}}
+			explicit := &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-service-optimizer", Namespace: "production"},
+				Spec: optimizationv1.ResourceOptimizerSpec{
+					TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+				},
+			}
+			r := newTestReconciler(optimizer, explicit, deployment, superseded, deselected)
+
+			_, err := r.reconcileSelector(ctx, optimizer)
+			Expect(err).NotTo(HaveOccurred())
+			expectDeleted(r, superseded)
+			expectDeleted(r, deselected)
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(HaveLen(1))
+			Expect(updated.Status.Targets[0].Name).To(Equal("api-service"))
+			Expect(updated.Status.Targets[0].PatchConfigMap).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,274 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// defaultTopOffenders matches the API default for spec.topOffenders.
+const defaultTopOffenders = 10
+
+// topOffendersLimit returns the configured number of top offenders, or the
+// default when unset.
+func topOffendersLimit(limit *int32) int {
+	if limit == nil {
+		return defaultTopOffenders
+	}
+	return int(*limit)
+}
+
+// workloadReport is the contribution of one recommendation to a report, over
+// all replicas of the workload.
+type workloadReport struct {
+	target    optimizationv1.TargetRef
+	optimizer string
+	team      string
+
+	requested   corev1.ResourceList
+	used        corev1.ResourceList
+	recommended corev1.ResourceList
+	waste       corev1.ResourceList
+	cost        *optimizationv1.CostEstimate
+	savings     float64
+}
+
+// newWorkloadReport scales the per-pod values of the recommendation to all
+// replicas. Resources left unmanaged count at their current requests.
+func newWorkloadReport(target optimizationv1.TargetRef, optimizer string, recommendation *optimizationv1.ResourceRecommendation) (*workloadReport, error) {
+	observed := recommendation.Observed
+	if observed == nil {
+		return nil, fmt.Errorf("recommendation for %s/%s has no observed resources", target.Namespace, target.Name)
+	}
+	replicas := int64(recommendation.CurrentReplicas)
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	requested, err := parseAmounts(observed.Requests)
+	if err != nil {
+		return nil, err
+	}
+	used, err := parseAmounts(observed.Usage)
+	if err != nil {
+		return nil, err
+	}
+	recommended, err := parseAmounts(optimizationv1.ResourceAmounts{
+		CPU:    recommendation.CPU.Request,
+		Memory: recommendation.Memory.Request,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, quantity := range requested {
+		if _, ok := recommended[name]; !ok {
+			recommended[name] = quantity
+		}
+	}
+
+	report := &workloadReport{
+		target:      target,
+		optimizer:   optimizer,
+		requested:   scaleResources(requested, replicas),
+		used:        scaleResources(used, replicas),
+		recommended: scaleResources(recommended, replicas),
+		waste:       corev1.ResourceList{},
+		cost:        recommendation.Cost,
+	}
+	for name, quantity := range report.requested {
+		waste := quantity.DeepCopy()
+		waste.Sub(report.recommended[name])
+		if waste.Sign() > 0 {
+			report.waste[name] = waste
+		}
+	}
+	if report.cost != nil {
+		if report.savings, err = strconv.ParseFloat(report.cost.MonthlySavings, 64); err != nil {
+			return nil, fmt.Errorf("invalid savings %q: %w", report.cost.MonthlySavings, err)
+		}
+	}
+	return report, nil
+}
+
+func parseAmounts(amounts optimizationv1.ResourceAmounts) (corev1.ResourceList, error) {
+	list := corev1.ResourceList{}
+	for name, value := range map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    amounts.CPU,
+		corev1.ResourceMemory: amounts.Memory,
+	} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s quantity %q: %w", name, value, err)
+		}
+		list[name] = quantity
+	}
+	return list, nil
+}
+
+func scaleResources(list corev1.ResourceList, factor int64) corev1.ResourceList {
+	scaled := corev1.ResourceList{}
+	for name, quantity := range list {
+		scaled[name] = *resource.NewMilliQuantity(quantity.MilliValue()*factor, quantity.Format)
+	}
+	return scaled
+}
+
+// reportAccumulator sums workload reports into a ReportSummary.
+type reportAccumulator struct {
+	workloads   int32
+	requested   corev1.ResourceList
+	used        corev1.ResourceList
+	recommended corev1.ResourceList
+	waste       corev1.ResourceList
+
+	costWorkloads   int32
+	currency        string
+	mixedCurrencies bool
+	currentCost     float64
+	recommendedCost float64
+}
+
+func newReportAccumulator() *reportAccumulator {
+	return &reportAccumulator{
+		requested:   corev1.ResourceList{},
+		used:        corev1.ResourceList{},
+		recommended: corev1.ResourceList{},
+		waste:       corev1.ResourceList{},
+	}
+}
+
+func (a *reportAccumulator) add(workload *workloadReport) {
+	a.workloads++
+	addResources(a.requested, workload.requested)
+	addResources(a.used, workload.used)
+	addResources(a.recommended, workload.recommended)
+	addResources(a.waste, workload.waste)
+
+	cost := workload.cost
+	if cost == nil {
+		return
+	}
+	if a.currency != "" && a.currency != cost.Currency {
+		a.mixedCurrencies = true
+	}
+	a.currency = cost.Currency
+	current, currentErr := strconv.ParseFloat(cost.CurrentMonthly, 64)
+	recommended, recommendedErr := strconv.ParseFloat(cost.RecommendedMonthly, 64)
+	if currentErr != nil || recommendedErr != nil {
+		return
+	}
+	a.costWorkloads++
+	a.currentCost += current
+	a.recommendedCost += recommended
+}
+
+func addResources(total, list corev1.ResourceList) {
+	for name, quantity := range list {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func (a *reportAccumulator) summary() optimizationv1.ReportSummary {
+	summary := optimizationv1.ReportSummary{
+		Workloads:   a.workloads,
+		Requested:   resourceAmounts(a.requested),
+		Used:        resourceAmounts(a.used),
+		Recommended: resourceAmounts(a.recommended),
+		Waste:       resourceAmounts(a.waste),
+	}
+	if a.costWorkloads > 0 && !a.mixedCurrencies {
+		summary.Cost = &optimizationv1.ReportCost{
+			Currency:           a.currency,
+			Workloads:          a.costWorkloads,
+			CurrentMonthly:     strconv.FormatFloat(a.currentCost, 'f', 2, 64),
+			RecommendedMonthly: strconv.FormatFloat(a.recommendedCost, 'f', 2, 64),
+			MonthlySavings:     strconv.FormatFloat(a.currentCost-a.recommendedCost, 'f', 2, 64),
+		}
+	}
+	return summary
+}
+
+// groupReports returns the summaries of the groups sorted by name.
+func groupReports(groups map[string]*reportAccumulator) []optimizationv1.GroupReport {
+	reports := make([]optimizationv1.GroupReport, 0, len(groups))
+	for name, group := range groups {
+		reports = append(reports, optimizationv1.GroupReport{Name: name, ReportSummary: group.summary()})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports
+}
+
+// topOffenders ranks the over-provisioned workloads by estimated savings,
+// then by CPU and memory waste, and returns the first limit of them. Savings
+// in different currencies cannot be compared, so if the estimates use more
+// than one currency the workloads are ranked by waste only.
+func topOffenders(workloads []*workloadReport, limit int) []optimizationv1.Offender {
+	var candidates []*workloadReport
+	currency, mixedCurrencies := "", false
+	for _, workload := range workloads {
+		if len(workload.waste) == 0 && workload.savings <= 0 {
+			continue
+		}
+		candidates = append(candidates, workload)
+		if workload.cost != nil {
+			if currency != "" && currency != workload.cost.Currency {
+				mixedCurrencies = true
+			}
+			currency = workload.cost.Currency
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !mixedCurrencies && a.savings != b.savings {
+			return a.savings > b.savings
+		}
+		if c := a.waste.Cpu().Cmp(*b.waste.Cpu()); c != 0 {
+			return c > 0
+		}
+		return a.waste.Memory().Cmp(*b.waste.Memory()) > 0
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	offenders := make([]optimizationv1.Offender, 0, len(candidates))
+	for _, workload := range candidates {
+		offender := optimizationv1.Offender{
+			TargetRef: workload.target,
+			Optimizer: workload.optimizer,
+			Waste:     resourceAmounts(workload.waste),
+		}
+		if workload.cost != nil {
+			offender.MonthlySavings = workload.cost.MonthlySavings
+			offender.Currency = workload.cost.Currency
+		}
+		offenders = append(offenders, offender)
+	}
+	return offenders
+}
@@ -18,19 +18,28 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
 	"github.com/stackbalancer/cost-optimizer-operator/internal/metrics"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/patch"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
@@ -54,6 +63,8 @@ type ResourceOptimizerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -71,58 +82,434 @@ func (r *ResourceOptimizerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		log.Error(err, "Failed to get resourceOptimizer")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	log.Info("Reconciling resourceOptimizer", "targetRef", resourceOptimizer.Spec.TargetRef, "policy", resourceOptimizer.Spec.Policy)
+	log.Info("Reconciling resourceOptimizer",
+		"targetRef", resourceOptimizer.Spec.TargetRef,
+		"targetSelector", resourceOptimizer.Spec.TargetSelector,
+		"policy", resourceOptimizer.Spec.Policy)
+
+	if resourceOptimizer.Spec.Suspend {
+		log.Info("ResourceOptimizer is suspended")
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"Suspended",
+			metav1.ConditionTrue,
+			"Suspended",
+			"Optimization is suspended, the last recommendation is kept",
+		)
+		// Resuming changes the spec, which triggers a new reconcile
+		return ctrl.Result{}, r.updateStatus(ctx, resourceOptimizer)
+	}
+	setCondition(
+		&resourceOptimizer.Status.Conditions,
+		resourceOptimizer.Generation,
+		"Suspended",
+		metav1.ConditionFalse,
+		"Active",
+		"Optimization is active",
+	)
+
+	if resourceOptimizer.Spec.TargetSelector != nil {
+		r.clearTargetRefStatus(ctx, resourceOptimizer)
+		return r.reconcileSelector(ctx, resourceOptimizer)
+	}
+	if resourceOptimizer.Spec.TargetRef == nil {
+		log.Info("ResourceOptimizer has no target")
+		return ctrl.Result{}, nil
+	}
+	r.clearSelectorStatus(ctx, resourceOptimizer)
+
+	// Check the grant before reading anything in the target namespace
+	targetNamespace := resourceOptimizer.Spec.TargetRef.Namespace
+	authorized, err := r.targetAuthorized(ctx, resourceOptimizer.Namespace, targetNamespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !authorized {
+		message := unauthorizedMessage(resourceOptimizer.Namespace, targetNamespace)
+		log.Info("Target namespace does not grant access", "namespace", targetNamespace)
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"Unauthorized",
+			metav1.ConditionTrue,
+			"GrantMissing",
+			message,
+		)
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"OptimizationReady",
+			metav1.ConditionFalse,
+			"Unauthorized",
+			message,
+		)
+		resourceOptimizer.Status.CurrentRecommendation = nil
+		meta.RemoveStatusCondition(&resourceOptimizer.Status.Conditions, optimizationv1.ConditionAdmissionRisk)
+		r.unpublishTargetRefPatch(ctx, resourceOptimizer, "the target namespace does not grant access")
+		r.recorder.Event(resourceOptimizer, corev1.EventTypeWarning, "Unauthorized", message)
+		if err := r.updateStatus(ctx, resourceOptimizer); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	}
+	setCondition(
+		&resourceOptimizer.Status.Conditions,
+		resourceOptimizer.Generation,
+		"Unauthorized",
+		metav1.ConditionFalse,
+		"Authorized",
+		"The target namespace allows this ResourceOptimizer",
+	)
 
 	// Get target deployment
 	deployment, err := r.getDeploymentObject(ctx, resourceOptimizer)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			addCondition(
-				&resourceOptimizer.Status,
+			setCondition(
+				&resourceOptimizer.Status.Conditions,
+				resourceOptimizer.Generation,
 				"DeploymentReady",
 				metav1.ConditionFalse,
 				"TargetNotFound",
 				"Target Deployment does not exist yet",
 			)
-			_ = r.updateStatus(ctx, resourceOptimizer)
+			if err := r.updateStatus(ctx, resourceOptimizer); err != nil {
+				return ctrl.Result{}, err
+			}
 			return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
-	addCondition(
-		&resourceOptimizer.Status,
+	setCondition(
+		&resourceOptimizer.Status.Conditions,
+		resourceOptimizer.Generation,
 		"DeploymentReady",
 		metav1.ConditionTrue,
 		"TargetFound",
 		"Target Deployment exists",
 	)
 
-	// Collect metrics and analyze
-	if err := r.analyzeAndOptimize(ctx, resourceOptimizer, deployment); err != nil {
-		log.Error(err, "Failed to analyze workload")
-		addCondition(
-			&resourceOptimizer.Status,
-			"OptimizationReady",
+	// The single target shares the top-level status fields
+	target := optimizationv1.TargetStatus{
+		TargetRef:      *resourceOptimizer.Spec.TargetRef,
+		Conditions:     resourceOptimizer.Status.Conditions,
+		Recommendation: resourceOptimizer.Status.CurrentRecommendation,
+		PatchConfigMap: resourceOptimizer.Status.PatchConfigMap,
+		History:        resourceOptimizer.Status.RecommendationHistory,
+	}
+	targetErr := r.reconcileTarget(ctx, resourceOptimizer, deployment, &target)
+	resourceOptimizer.Status.Conditions = target.Conditions
+	resourceOptimizer.Status.CurrentRecommendation = target.Recommendation
+	resourceOptimizer.Status.PatchConfigMap = target.PatchConfigMap
+	resourceOptimizer.Status.RecommendationHistory = target.History
+	if targetErr != nil {
+		if err := r.updateStatus(ctx, resourceOptimizer); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute * 10}, nil
+	}
+
+	if err := r.updateStatus(ctx, resourceOptimizer); err != nil {
+		log.Error(err, "Failed to update ResourceOptimizer status")
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Reconciliation complete")
+
+	return ctrl.Result{RequeueAfter: time.Minute * 15}, nil
+
+}
+
+// clearTargetRefStatus drops the status of a single targetRef, left behind
+// when the optimizer switched to a targetSelector, and deletes its patch.
+func (r *ResourceOptimizerReconciler) clearTargetRefStatus(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer) {
+	r.unpublishTargetRefPatch(ctx, resourceOptimizer, "the optimizer uses a target selector")
+	resourceOptimizer.Status.CurrentRecommendation = nil
+	resourceOptimizer.Status.RecommendationHistory = nil
+	meta.RemoveStatusCondition(&resourceOptimizer.Status.Conditions, optimizationv1.ConditionAdmissionRisk)
+}
+
+// unpublishTargetRefPatch deletes the patch published for a single targetRef,
+// whose status shares the top-level fields. Failures are logged and the
+// reference kept, so the deletion is retried.
+func (r *ResourceOptimizerReconciler) unpublishTargetRefPatch(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer, reason string) {
+	if resourceOptimizer.Status.PatchConfigMap == "" {
+		return
+	}
+	target := optimizationv1.TargetStatus{
+		PatchConfigMap: resourceOptimizer.Status.PatchConfigMap,
+		Conditions:     resourceOptimizer.Status.Conditions,
+	}
+	if err := r.unpublishPatch(ctx, resourceOptimizer, &target, reason); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to delete recommendation patch", "configMap", target.PatchConfigMap)
+		return
+	}
+	resourceOptimizer.Status.PatchConfigMap = ""
+	resourceOptimizer.Status.Conditions = target.Conditions
+}
+
+// clearSelectorStatus drops the per-target status of a targetSelector, left
+// behind when the optimizer switched to a single targetRef, and deletes the
+// patches published for it.
+func (r *ResourceOptimizerReconciler) clearSelectorStatus(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer) {
+	var kept []optimizationv1.TargetStatus
+	for _, target := range resourceOptimizer.Status.Targets {
+		if target.PatchConfigMap == "" {
+			continue
+		}
+		if err := r.unpublishPatch(ctx, resourceOptimizer, &target, "the optimizer uses a target reference"); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to delete recommendation patch", "configMap", target.PatchConfigMap)
+			// Keep the reference so the deletion is retried
+			kept = append(kept, optimizationv1.TargetStatus{TargetRef: target.TargetRef, PatchConfigMap: target.PatchConfigMap})
+		}
+	}
+	resourceOptimizer.Status.Targets = kept
+	meta.RemoveStatusCondition(&resourceOptimizer.Status.Conditions, "StatusTrimmed")
+}
+
+// reconcileSelector optimizes every workload matched by spec.targetSelector
+// and reports the per-workload results in status.targets.
+func (r *ResourceOptimizerReconciler) reconcileSelector(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	deployments, unauthorized, err := r.selectDeployments(ctx, resourceOptimizer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(unauthorized) > 0 {
+		message := unauthorizedMessage(resourceOptimizer.Namespace, unauthorized...)
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"Unauthorized",
+			metav1.ConditionTrue,
+			"GrantMissing",
+			message,
+		)
+		r.recorder.Event(resourceOptimizer, corev1.EventTypeWarning, "Unauthorized", message)
+	} else {
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"Unauthorized",
 			metav1.ConditionFalse,
-			"AnalysisFailed",
-			err.Error(),
+			"Authorized",
+			"All selected namespaces allow this ResourceOptimizer",
 		)
-		_ = r.updateStatus(ctx, resourceOptimizer)
-		return ctrl.Result{RequeueAfter: time.Minute * 10}, nil
+	}
+	if len(deployments) == 0 {
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"DeploymentReady",
+			metav1.ConditionFalse,
+			"TargetNotFound",
+			"No Deployment matches the target selector",
+		)
+		resourceOptimizer.Status.Targets = nil
+		if err := r.updateStatus(ctx, resourceOptimizer); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
 	}
 
-	addCondition(&resourceOptimizer.Status, "Ready", metav1.ConditionTrue, "AllSubresourcesReady", "All subresources are ready")
+	setCondition(
+		&resourceOptimizer.Status.Conditions,
+		resourceOptimizer.Generation,
+		"DeploymentReady",
+		metav1.ConditionTrue,
+		"TargetFound",
+		fmt.Sprintf("%d Deployments match the target selector", len(deployments)),
+	)
+
+	explicit, err := r.explicitTargets(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous := make(map[optimizationv1.TargetRef]optimizationv1.TargetStatus, len(resourceOptimizer.Status.Targets))
+	for _, target := range resourceOptimizer.Status.Targets {
+		previous[target.TargetRef] = target
+	}
+
+	targets := make([]optimizationv1.TargetStatus, 0, len(deployments))
+	recommended := 0
+	for i := range deployments {
+		deployment := &deployments[i]
+		ref := optimizationv1.TargetRef{Kind: "Deployment", Name: deployment.Name, Namespace: deployment.Namespace}
+		target := previous[ref]
+		target.TargetRef = ref
+
+		owner, ok := explicit[ref]
+		if ok && owner.Namespace != deployment.Namespace {
+			// An explicit target only takes precedence if it is allowed to act on the workload
+			if ok, err = r.targetAuthorized(ctx, owner.Namespace, deployment.Namespace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if ok {
+			// A ResourceOptimizer naming the workload in its targetRef takes precedence
+			dropRecommendation(&target)
+			if target.PatchConfigMap != "" {
+				if err := r.unpublishPatch(ctx, resourceOptimizer, &target, "the workload is managed by another optimizer"); err != nil {
+					log.Error(err, "Failed to delete recommendation patch", "configMap", target.PatchConfigMap)
+				}
+			}
+			setCondition(
+				&target.Conditions,
+				resourceOptimizer.Generation,
+				"OptimizationReady",
+				metav1.ConditionFalse,
+				"Superseded",
+				fmt.Sprintf("Deployment is targeted explicitly by ResourceOptimizer %s", owner),
+			)
+		} else if err := r.reconcileTarget(ctx, resourceOptimizer, deployment, &target); err != nil {
+			log.Error(err, "Failed to optimize selected workload", "deployment", client.ObjectKeyFromObject(deployment))
+		}
+
+		if target.Recommendation != nil {
+			recommended++
+		}
+		targets = append(targets, target)
+		delete(previous, ref)
+	}
+	// Workloads that left the selector no longer get a patch
+	for _, target := range previous {
+		if target.PatchConfigMap == "" {
+			continue
+		}
+		if err := r.unpublishPatch(ctx, resourceOptimizer, &target, "the workload is no longer selected"); err != nil {
+			log.Error(err, "Failed to delete recommendation patch", "configMap", target.PatchConfigMap)
+			// Keep the reference so the deletion is retried
+			targets = append(targets, optimizationv1.TargetStatus{TargetRef: target.TargetRef, PatchConfigMap: target.PatchConfigMap})
+		}
+	}
+	if trimmed := trimTargets(targets, maxTargetsSize); trimmed != "" {
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"StatusTrimmed",
+			metav1.ConditionTrue,
+			"SizeLimitReached",
+			fmt.Sprintf("Dropped %s of %d targets to keep the status within the object size limit", trimmed, len(targets)),
+		)
+	} else {
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"StatusTrimmed",
+			metav1.ConditionFalse,
+			"WithinSizeLimit",
+			"The status of all targets is complete",
+		)
+	}
+	resourceOptimizer.Status.Targets = targets
+
+	message := fmt.Sprintf("%d of %d selected Deployments have a recommendation", recommended, len(deployments))
+	if recommended > 0 {
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"OptimizationReady",
+			metav1.ConditionTrue,
+			"RecommendationGenerated",
+			message,
+		)
+	} else {
+		setCondition(
+			&resourceOptimizer.Status.Conditions,
+			resourceOptimizer.Generation,
+			"OptimizationReady",
+			metav1.ConditionFalse,
+			"NoRecommendations",
+			message,
+		)
+	}
 
 	if err := r.updateStatus(ctx, resourceOptimizer); err != nil {
 		log.Error(err, "Failed to update ResourceOptimizer status")
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Reconciliation complete")
+	log.Info("Reconciliation complete", "targets", len(targets), "recommended", recommended)
 
 	return ctrl.Result{RequeueAfter: time.Minute * 15}, nil
+}
+
+// reconcileTarget analyzes a single workload and publishes its patch,
+// recording the outcome and history on target. Analysis failures are
+// recorded as a condition and returned.
+func (r *ResourceOptimizerReconciler) reconcileTarget(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer, deployment *appsv1.Deployment, target *optimizationv1.TargetStatus) error {
+	log := logf.FromContext(ctx)
 
+	// Collect metrics and analyze
+	if err := r.analyzeAndOptimize(ctx, resourceOptimizer, resourceOptimizer.Spec.Policy, deployment, target); err != nil {
+		log.Error(err, "Failed to analyze workload")
+		setCondition(
+			&target.Conditions,
+			resourceOptimizer.Generation,
+			"OptimizationReady",
+			metav1.ConditionFalse,
+			"AnalysisFailed",
+			err.Error(),
+		)
+		return err
+	}
+
+	// Pods created in "Initial" mode pick up the recommendation right away,
+	// unless the API server would reject them
+	admissionRisk := meta.IsStatusConditionTrue(target.Conditions, optimizationv1.ConditionAdmissionRisk)
+	applied := resourceOptimizer.Spec.UpdateMode == optimizationv1.UpdateModeInitial && !admissionRisk
+	if resourceOptimizer.Spec.PatchOutput != nil && target.Recommendation != nil && admissionRisk {
+		setCondition(
+			&target.Conditions,
+			resourceOptimizer.Generation,
+			"PatchPublished",
+			metav1.ConditionFalse,
+			"AdmissionRisk",
+			"Recommendation not published, it would be rejected by admission",
+		)
+	} else if resourceOptimizer.Spec.PatchOutput != nil && target.Recommendation != nil {
+		if err := r.publishPatch(ctx, resourceOptimizer, deployment, target); err != nil {
+			log.Error(err, "Failed to publish recommendation patch")
+			reason := "PublishFailed"
+			if errors.Is(err, errConfigMapNotOwned) {
+				reason = "ConfigMapConflict"
+			}
+			setCondition(
+				&target.Conditions,
+				resourceOptimizer.Generation,
+				"PatchPublished",
+				metav1.ConditionFalse,
+				reason,
+				err.Error(),
+			)
+		} else {
+			setCondition(
+				&target.Conditions,
+				resourceOptimizer.Generation,
+				"PatchPublished",
+				metav1.ConditionTrue,
+				"PatchPublished",
+				fmt.Sprintf("Recommendation published to ConfigMap %s", target.PatchConfigMap),
+			)
+			applied = true
+		}
+	} else if target.PatchConfigMap != "" {
+		// Excluded or refused targets must not keep a stale patch applied
+		reason := "the target has no recommendation"
+		if resourceOptimizer.Spec.PatchOutput == nil {
+			reason = "patch output is disabled"
+		}
+		if err := r.unpublishPatch(ctx, resourceOptimizer, target, reason); err != nil {
+			log.Error(err, "Failed to delete recommendation patch", "configMap", target.PatchConfigMap)
+		}
+	}
+	recordHistory(target, deployment.Generation, applied, historyLimit(resourceOptimizer.Spec.HistoryLimit))
+
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -146,45 +533,50 @@ func (r *ResourceOptimizerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.metricsCollector = metrics.NewCollector(kubeClient, metricsClient)
 	r.analyzer = metrics.NewAnalyzer()
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&optimizationv1.ResourceOptimizer{}).
-		Named("resourceoptimizer").
-		Complete(r)
-}
-
-// Function to add a condition to the ResourceOptimizerStatus
-func addCondition(status *optimizationv1.ResourceOptimizerStatus, condType string, statusType metav1.ConditionStatus, reason, message string) {
-	now := metav1.Now()
-
-	for i, existingCondition := range status.Conditions {
-		if existingCondition.Type == condType {
-			if existingCondition.Status != statusType {
-				status.Conditions[i].LastTransitionTime = now
-			}
-			// Condition already exists, update it
-			status.Conditions[i].Status = statusType
-			status.Conditions[i].Reason = reason
-			status.Conditions[i].Message = message
-			status.Conditions[i].LastTransitionTime = metav1.Now()
-			return
-		}
+	// Recommendations are exported from the cache at scrape time
+	if err := registerOptimizerCollector(mgr.GetClient()); err != nil {
+		return err
 	}
 
-	// Condition does not exist, add it
-	condition := metav1.Condition{
-		Type:               condType,
-		Status:             statusType,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &optimizationv1.ResourceOptimizer{},
+		targetIndexKey, targetIndexValues); err != nil {
+		return err
 	}
-	status.Conditions = append(status.Conditions, condition)
+
+	// Status updates do not bump the generation, so the reconciler does not
+	// trigger itself. Target workloads are watched so status follows them
+	// without waiting for the periodic requeue, and their pods wake up the
+	// optimizers still waiting for a target or metrics to analyze.
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&optimizationv1.ResourceOptimizer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&appsv1.Deployment{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForDeployment),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{},
+				predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForPod),
+			builder.WithPredicates(podReadinessChanged)).
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(predicate.Or(predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Named("resourceoptimizer").
+		Complete(r)
 }
 
-// Function to update the status of the resourceOptimizer object
+// Function to update the status of the resourceOptimizer object. The summary
+// conditions and observedGeneration are derived right before writing.
+// Failed writes are surfaced as an event and metric.
 func (r *ResourceOptimizerReconciler) updateStatus(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer) error {
-	// Update the status of the resourceOptimizer object
-	if err := r.Status().Update(ctx, resourceOptimizer); err != nil {
+	summarizeConditions(&resourceOptimizer.Status.Conditions, resourceOptimizer.Generation)
+	resourceOptimizer.Status.ObservedGeneration = resourceOptimizer.Generation
+
+	err := patchStatus(ctx, r.Client, resourceOptimizer, func(latest *optimizationv1.ResourceOptimizer) {
+		latest.Status = resourceOptimizer.Status
+	})
+	if client.IgnoreNotFound(err) != nil {
+		statusUpdateFailuresTotal.WithLabelValues("ResourceOptimizer").Inc()
+		r.recorder.Eventf(resourceOptimizer, corev1.EventTypeWarning, "StatusUpdateFailed", "Failed to update status: %v", err)
 		return err
 	}
 
@@ -212,19 +604,125 @@ func (r *ResourceOptimizerReconciler) getDeploymentObject(ctx context.Context, r
 	return existingDeployment, nil
 }
 
-func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer, deployment *appsv1.Deployment) error {
+// analyzeAndOptimize generates a recommendation for the deployment under the
+// given policy and records it on target. Events are recorded on owner, the
+// optimizer the analysis is performed for.
+func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, owner client.Object, policy optimizationv1.Policy, deployment *appsv1.Deployment, target *optimizationv1.TargetStatus) error {
 	log := logf.FromContext(ctx)
 
+	// App owners can opt the workload out or freeze resources with annotations
+	if optimizationv1.AnnotationEnabled(deployment.Annotations, optimizationv1.AnnotationExclude) {
+		log.Info("Workload opted out of optimization", "deployment", client.ObjectKeyFromObject(deployment))
+		setCondition(
+			&target.Conditions,
+			owner.GetGeneration(),
+			"OptimizationReady",
+			metav1.ConditionFalse,
+			"Excluded",
+			fmt.Sprintf("Workload opted out with the %s annotation", optimizationv1.AnnotationExclude),
+		)
+		dropRecommendation(target)
+		return nil
+	}
+	pinCPU := optimizationv1.AnnotationEnabled(deployment.Annotations, optimizationv1.AnnotationPinCPU)
+	pinMemory := optimizationv1.AnnotationEnabled(deployment.Annotations, optimizationv1.AnnotationPinMemory)
+
+	// Check for HPAs scaling on a resource we would resize before doing any work.
+	// Pinned resources are never resized, so HPAs scaling on them do not conflict.
+	allConflicts, err := r.findHPAConflicts(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	var conflicts []hpaConflict
+	for _, conflict := range allConflicts {
+		if (conflict.resource == corev1.ResourceCPU && pinCPU) || (conflict.resource == corev1.ResourceMemory && pinMemory) {
+			continue
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	var skipCPU, skipMemory bool
+	if len(conflicts) == 0 {
+		setCondition(
+			&target.Conditions,
+			owner.GetGeneration(),
+			"ConflictsWithHPA",
+			metav1.ConditionFalse,
+			"NoConflict",
+			"No HPA scales the target on CPU or memory utilization",
+		)
+	} else {
+		message := describeHPAConflicts(conflicts)
+		switch policy.HPAConflict {
+		case optimizationv1.HPAConflictSkipConflicting:
+			for _, conflict := range conflicts {
+				skipCPU = skipCPU || conflict.resource == corev1.ResourceCPU
+				skipMemory = skipMemory || conflict.resource == corev1.ResourceMemory
+			}
+			setCondition(
+				&target.Conditions,
+				owner.GetGeneration(),
+				"ConflictsWithHPA",
+				metav1.ConditionTrue,
+				"ResourcesSkipped",
+				message,
+			)
+		case optimizationv1.HPAConflictAlignTarget:
+			for _, conflict := range conflicts {
+				if conflict.resource == corev1.ResourceCPU {
+					policy.Cpu.TargetUtilization = conflict.targetUtilization
+				} else {
+					skipMemory = true
+				}
+			}
+			setCondition(
+				&target.Conditions,
+				owner.GetGeneration(),
+				"ConflictsWithHPA",
+				metav1.ConditionTrue,
+				"TargetAligned",
+				message,
+			)
+		default:
+			log.Info("Refusing to recommend resources scaled by an HPA", "conflicts", message)
+			if c := meta.FindStatusCondition(target.Conditions, "ConflictsWithHPA"); c == nil ||
+				c.Status != metav1.ConditionTrue || c.Reason != "Refused" {
+				r.recorder.Eventf(owner, corev1.EventTypeWarning, "ConflictsWithHPA",
+					"Deployment %s: %s", client.ObjectKeyFromObject(deployment), message)
+			}
+			setCondition(
+				&target.Conditions,
+				owner.GetGeneration(),
+				"ConflictsWithHPA",
+				metav1.ConditionTrue,
+				"Refused",
+				message,
+			)
+			setCondition(
+				&target.Conditions,
+				owner.GetGeneration(),
+				"OptimizationReady",
+				metav1.ConditionFalse,
+				"ConflictsWithHPA",
+				"Recommendations withheld: "+message,
+			)
+			dropRecommendation(target)
+			return nil
+		}
+	}
+
 	// Collect current metrics
 	workloadMetrics, err := r.metricsCollector.CollectWorkloadMetrics(ctx, deployment)
 	if err != nil {
+		metricsAPIErrorsTotal.WithLabelValues(deployment.Namespace, deployment.Name).Inc()
 		return err
 	}
 
 	if len(workloadMetrics.Usage) == 0 {
 		log.Info("No metrics data available yet, skipping optimization")
-		addCondition(
-			&resourceOptimizer.Status,
+		setCondition(
+			&target.Conditions,
+			owner.GetGeneration(),
 			"OptimizationReady",
 			metav1.ConditionFalse,
 			"NoMetricsData",
@@ -233,11 +731,53 @@ func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, re
 		return nil
 	}
 
+	// Report workloads that are candidates for scaling to zero
+	idle, err := r.updateIdleCondition(owner, policy, deployment, workloadMetrics, target)
+	if err != nil {
+		return err
+	}
+
+	// A leak would only be fed by more headroom, so memory is left alone
+	memoryGrowth := r.updateMemoryGrowthCondition(owner, policy, deployment, workloadMetrics, target)
+
 	// Generate recommendations
-	recommendation, err := r.analyzer.GenerateRecommendation(workloadMetrics, resourceOptimizer.Spec.Policy)
+	recommendation, err := r.analyzer.GenerateRecommendation(workloadMetrics, policy)
+	if errors.Is(err, metrics.ErrOnlyStartupSamples) {
+		log.Info("All pods are within the startup grace period, skipping optimization")
+		setCondition(
+			&target.Conditions,
+			owner.GetGeneration(),
+			"OptimizationReady",
+			metav1.ConditionFalse,
+			"WarmingUp",
+			"Waiting for containers to finish the startup grace period",
+		)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	if idle {
+		recommendation.Reason += "; workload is idle, consider scaling to zero or deleting it"
+	}
+	if skipCPU {
+		recommendation.Reason += "; cpu left unmanaged due to HPA"
+	}
+	if skipMemory {
+		recommendation.Reason += "; memory left unmanaged due to HPA"
+	}
+	if pinCPU {
+		recommendation.Reason += "; cpu pinned by annotation"
+	}
+	if pinMemory {
+		recommendation.Reason += "; memory pinned by annotation"
+	}
+	if memoryGrowth {
+		recommendation.Reason += "; memory left unmanaged due to steady growth"
+	}
+	if excluded := deployment.Annotations[optimizationv1.AnnotationExcludeContainers]; excluded != "" {
+		recommendation.Reason += "; containers excluded by annotation: " + excluded
+	}
 
 	log.Info("Generated optimization recommendation",
 		"cpuRequest", recommendation.CPURequest.String(),
@@ -248,8 +788,9 @@ func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, re
 		"reason", recommendation.Reason)
 
 	// Record recommendation event
-	r.recorder.Eventf(resourceOptimizer, corev1.EventTypeNormal, "RecommendationGenerated",
-		"CPU: %s/%s, Memory: %s/%s (confidence: %.2f)",
+	r.recorder.Eventf(owner, corev1.EventTypeNormal, "RecommendationGenerated",
+		"Deployment %s: CPU: %s/%s, Memory: %s/%s (confidence: %.2f)",
+		client.ObjectKeyFromObject(deployment),
 		recommendation.CPURequest.String(),
 		recommendation.CPULimit.String(),
 		recommendation.MemoryRequest.String(),
@@ -257,7 +798,7 @@ func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, re
 		recommendation.Confidence)
 
 	// Update status with recommendation
-	resourceOptimizer.Status.CurrentRecommendation = &optimizationv1.ResourceRecommendation{
+	result := &optimizationv1.ResourceRecommendation{
 		CPU: optimizationv1.CPURecommendation{
 			Request: recommendation.CPURequest.String(),
 			Limit:   recommendation.CPULimit.String(),
@@ -266,13 +807,46 @@ func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, re
 			Request: recommendation.MemoryRequest.String(),
 			Limit:   recommendation.MemoryLimit.String(),
 		},
-		Confidence:  int32(recommendation.Confidence * 100),
-		Reason:      recommendation.Reason,
-		GeneratedAt: metav1.Now(),
+		CurrentReplicas:      recommendation.CurrentReplicas,
+		StartupPeak:          startupPeak(recommendation),
+		Observed:             r.observedResources(deployment, workloadMetrics),
+		Replicas:             replicaRecommendation(recommendation.Replicas),
+		TargetShapeReplicas:  replicaRecommendation(recommendation.TargetShapeReplicas),
+		Confidence:           int32(recommendation.Confidence * 100),
+		ConfidenceComponents: confidenceComponents(recommendation.Components),
+		Reason:               recommendation.Reason,
+		Explanation:          explanation(recommendation.Explanation, policy),
+		GeneratedAt:          metav1.Now(),
+	}
+	switch {
+	case skipCPU:
+		result.Explanation.CPU.Unmanaged = "HPA"
+	case pinCPU:
+		result.Explanation.CPU.Unmanaged = "Pinned"
 	}
+	switch {
+	case skipMemory:
+		result.Explanation.Memory.Unmanaged = "HPA"
+	case pinMemory:
+		result.Explanation.Memory.Unmanaged = "Pinned"
+	case memoryGrowth:
+		result.Explanation.Memory.Unmanaged = "MemoryGrowth"
+	}
+	if skipCPU || pinCPU {
+		result.CPU = optimizationv1.CPURecommendation{}
+	}
+	if skipMemory || pinMemory || memoryGrowth {
+		result.Memory = optimizationv1.MemoryRecommendation{}
+	}
+	if err := r.applyAdmissionConstraints(ctx, owner, deployment, result, target); err != nil {
+		return err
+	}
+	r.updateCostEstimate(ctx, owner, policy, deployment, result, target)
+	target.Recommendation = result
 
-	addCondition(
-		&resourceOptimizer.Status,
+	setCondition(
+		&target.Conditions,
+		owner.GetGeneration(),
 		"OptimizationReady",
 		metav1.ConditionTrue,
 		"RecommendationGenerated",
@@ -281,3 +855,194 @@ func (r *ResourceOptimizerReconciler) analyzeAndOptimize(ctx context.Context, re
 
 	return nil
 }
+
+// startupPeak returns the peak usage of the startup samples left out of the
+// recommendation, or nil if there were none.
+func startupPeak(recommendation *metrics.Recommendation) *optimizationv1.ResourceAmounts {
+	if recommendation.StartupPeakCPU == nil || recommendation.StartupPeakMemory == nil {
+		return nil
+	}
+	return &optimizationv1.ResourceAmounts{
+		CPU:    recommendation.StartupPeakCPU.String(),
+		Memory: recommendation.StartupPeakMemory.String(),
+	}
+}
+
+// explanation converts the explanation of the analyzer and adds the policy
+// inputs it was computed with.
+func explanation(explanation metrics.Explanation, policy optimizationv1.Policy) *optimizationv1.RecommendationExplanation {
+	resourceExplanation := func(resource metrics.ResourceExplanation) optimizationv1.ResourceExplanation {
+		return optimizationv1.ResourceExplanation{
+			Usage: optimizationv1.UsageStatistics{
+				Average:          resource.Average.String(),
+				Peak:             resource.Peak.String(),
+				P50:              resource.P50.String(),
+				P90:              resource.P90.String(),
+				P99:              resource.P99.String(),
+				OutliersRejected: int32(resource.OutliersRejected),
+			},
+			Target:     resource.Target.String(),
+			ClampedBy:  resource.ClampedBy,
+			LimitBasis: resource.LimitBasis,
+		}
+	}
+	return &optimizationv1.RecommendationExplanation{
+		Window:         metav1.Duration{Duration: explanation.Window.Round(time.Second)},
+		Samples:        int32(explanation.Samples),
+		Pods:           int32(explanation.Pods),
+		StartupSamples: int32(explanation.StartupSamples),
+		CPU: optimizationv1.CPUExplanation{
+			ResourceExplanation: resourceExplanation(explanation.CPU),
+			Policy:              policy.Cpu,
+		},
+		Memory: optimizationv1.MemoryExplanation{
+			ResourceExplanation: resourceExplanation(explanation.Memory),
+			Policy:              policy.Memory,
+		},
+	}
+}
+
+// confidenceComponents converts the confidence inputs of the analyzer to percentages.
+func confidenceComponents(components metrics.ConfidenceComponents) *optimizationv1.ConfidenceComponents {
+	percent := func(fraction float64) int32 {
+		return int32(math.Round(fraction * 100))
+	}
+	return &optimizationv1.ConfidenceComponents{
+		HistoryDuration: metav1.Duration{Duration: components.HistoryDuration.Round(time.Second)},
+		Coverage:        percent(components.Coverage),
+		CPUVariation:    percent(components.CPUVariation),
+		MemoryVariation: percent(components.MemoryVariation),
+		Pods:            int32(components.Pods),
+		Gaps:            percent(components.Gaps),
+	}
+}
+
+// observedResources reports the requests and the average usage per pod of
+// the managed containers the analysis was based on.
+func (r *ResourceOptimizerReconciler) observedResources(deployment *appsv1.Deployment, workloadMetrics *metrics.WorkloadMetrics) *optimizationv1.ObservedResources {
+	cpuUsage, memoryUsage := r.metricsCollector.GetAverageUsage(workloadMetrics, time.Hour)
+	return &optimizationv1.ObservedResources{
+		Requests: resourceAmounts(managedRequests(deployment)),
+		Usage: optimizationv1.ResourceAmounts{
+			CPU:    cpuUsage.String(),
+			Memory: memoryUsage.String(),
+		},
+	}
+}
+
+// resourceAmounts returns the CPU and memory of a resource list, leaving
+// missing resources empty.
+func resourceAmounts(list corev1.ResourceList) optimizationv1.ResourceAmounts {
+	var amounts optimizationv1.ResourceAmounts
+	if quantity, ok := list[corev1.ResourceCPU]; ok {
+		amounts.CPU = quantity.String()
+	}
+	if quantity, ok := list[corev1.ResourceMemory]; ok {
+		amounts.Memory = quantity.String()
+	}
+	return amounts
+}
+
+// replicaRecommendation converts an analyzer replica range into its status representation.
+func replicaRecommendation(replicas *metrics.ReplicaRange) *optimizationv1.ReplicaRecommendation {
+	if replicas == nil {
+		return nil
+	}
+	return &optimizationv1.ReplicaRecommendation{
+		PodCPU:      replicas.PodCPU.String(),
+		PodMemory:   replicas.PodMemory.String(),
+		MinReplicas: replicas.Min,
+		MaxReplicas: replicas.Max,
+		TotalCPU:    replicas.TotalCPU.String(),
+		TotalMemory: replicas.TotalMemory.String(),
+	}
+}
+
+// errConfigMapNotOwned is returned when the patch ConfigMap exists but is not
+// controlled by the ResourceOptimizer publishing to it.
+var errConfigMapNotOwned = errors.New("ConfigMap exists and is not controlled by this ResourceOptimizer")
+
+// publishPatch renders the current recommendation in the configured GitOps
+// format and stores it in a ConfigMap owned by the ResourceOptimizer. A
+// ConfigMap of the same name created by someone else is left alone.
+func (r *ResourceOptimizerReconciler) publishPatch(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer, deployment *appsv1.Deployment, target *optimizationv1.TargetStatus) error {
+	output := *resourceOptimizer.Spec.PatchOutput
+
+	containers := optimizationv1.ManagedContainers(deployment.Annotations, deployment.Spec.Template.Spec.Containers)
+	if len(containers) != 1 {
+		return fmt.Errorf("patch output supports workloads with a single managed container only, %s has %d",
+			deployment.Name, len(containers))
+	}
+
+	resources, err := target.Recommendation.ResourceRequirements()
+	if err != nil {
+		return err
+	}
+
+	key, content, err := patch.Render(output, deployment, containers[0], resources,
+		client.ObjectKeyFromObject(resourceOptimizer).String())
+	if err != nil {
+		return err
+	}
+
+	name := output.ConfigMapName
+	if name == "" {
+		name = resourceOptimizer.Name + "-patch"
+	}
+	if resourceOptimizer.Spec.TargetSelector != nil {
+		// One ConfigMap per selected workload
+		name = fmt.Sprintf("%s-%s-%s", name, deployment.Namespace, deployment.Name)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: resourceOptimizer.Namespace,
+		},
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.ResourceVersion != "" && !metav1.IsControlledBy(configMap, resourceOptimizer) {
+			return fmt.Errorf("%w: %s", errConfigMapNotOwned, name)
+		}
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels["app.kubernetes.io/managed-by"] = "cost-optimizer-operator"
+		// Replace the whole payload so switching formats does not leave stale keys behind.
+		configMap.Data = map[string]string{key: content}
+		return controllerutil.SetControllerReference(resourceOptimizer, configMap, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+
+	target.PatchConfigMap = name
+	if result != controllerutil.OperationResultNone {
+		patchesPublishedTotal.WithLabelValues(client.ObjectKeyFromObject(resourceOptimizer).String()).Inc()
+		r.recorder.Eventf(resourceOptimizer, corev1.EventTypeNormal, "PatchPublished",
+			"Recommendation patch %s in ConfigMap %s", result, name)
+	}
+	return nil
+}
+
+// unpublishPatch deletes the ConfigMap a patch was published to once it is no
+// longer wanted, and clears it from the target status. reason completes the
+// event recorded for the deletion.
+func (r *ResourceOptimizerReconciler) unpublishPatch(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer, target *optimizationv1.TargetStatus, reason string) error {
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: resourceOptimizer.Namespace, Name: target.PatchConfigMap}, configMap)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && metav1.IsControlledBy(configMap, resourceOptimizer) {
+		if err := r.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		r.recorder.Eventf(resourceOptimizer, corev1.EventTypeNormal, "PatchDeleted",
+			"Recommendation patch ConfigMap %s deleted, %s", target.PatchConfigMap, reason)
+	}
+
+	target.PatchConfigMap = ""
+	meta.RemoveStatusCondition(&target.Conditions, "PatchPublished")
+	return nil
+}
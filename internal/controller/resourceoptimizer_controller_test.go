@@ -18,16 +18,30 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/metrics"
 )
 
 var _ = Describe("ResourceOptimizer Controller", func() {
@@ -43,6 +57,7 @@ var _ = Describe("ResourceOptimizer Controller", func() {
 		resourceoptimizer := &optimizationv1.ResourceOptimizer{}
 
 		BeforeEach(func() {
+			requireEnvtest()
 			By("creating the custom resource for the Kind ResourceOptimizer")
 			err := k8sClient.Get(ctx, typeNamespacedName, resourceoptimizer)
 			if err != nil && errors.IsNotFound(err) {
@@ -58,6 +73,9 @@ var _ = Describe("ResourceOptimizer Controller", func() {
 		})
 
 		AfterEach(func() {
+			if k8sClient == nil {
+				return
+			}
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &optimizationv1.ResourceOptimizer{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
@@ -81,4 +99,229 @@ var _ = Describe("ResourceOptimizer Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When the ResourceOptimizer is suspended", func() {
+		It("should keep the last recommendation without analyzing the target", func() {
+			ctx := context.Background()
+			recommendation := &optimizationv1.ResourceRecommendation{
+				CPU:    optimizationv1.CPURecommendation{Request: "200m", Limit: "400m"},
+				Memory: optimizationv1.MemoryRecommendation{Request: "256Mi", Limit: "512Mi"},
+				Reason: "Based on earlier usage",
+			}
+			optimizer := &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-service-optimizer", Namespace: "production", Generation: 2},
+				Spec: optimizationv1.ResourceOptimizerSpec{
+					TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+					Policy: optimizationv1.Policy{
+						Cpu:    optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+						Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+					},
+					Suspend: true,
+				},
+				Status: optimizationv1.ResourceOptimizerStatus{CurrentRecommendation: recommendation.DeepCopy()},
+			}
+			r := newTestReconciler(optimizer, testDeployment(2, corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}))
+
+			// Count every read of the metrics API
+			collections := 0
+			metricsClient := metricsfake.NewSimpleClientset()
+			metricsClient.PrependReactor("*", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+				collections++
+				return true, &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{
+					testPodMetrics("api-service-1", "50m", "100Mi"),
+				}}, nil
+			})
+			r.metricsCollector = metrics.NewCollector(nil, metricsClient)
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(collections).To(BeZero())
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			Expect(updated.Status.CurrentRecommendation).To(Equal(recommendation))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "Suspended")).To(BeTrue())
+			Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+		})
+	})
+
+	Context("When switching between targetRef and targetSelector", func() {
+		var (
+			ctx            context.Context
+			recommendation *optimizationv1.ResourceRecommendation
+			optimizer      *optimizationv1.ResourceOptimizer
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			recommendation = &optimizationv1.ResourceRecommendation{
+				CPU:        optimizationv1.CPURecommendation{Request: "200m", Limit: "400m"},
+				Memory:     optimizationv1.MemoryRecommendation{Request: "256Mi", Limit: "512Mi"},
+				Confidence: 80,
+			}
+			optimizer = &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-service-optimizer", Namespace: "production"},
+				Spec: optimizationv1.ResourceOptimizerSpec{
+					Policy: optimizationv1.Policy{
+						Cpu:    optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+						Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+					},
+				},
+			}
+		})
+
+		// ownedPatch returns a patch ConfigMap controlled by the optimizer
+		ownedPatch := func(name string) *corev1.ConfigMap {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production"}}
+			Expect(controllerutil.SetControllerReference(optimizer, configMap, scheme.Scheme)).To(Succeed())
+			return configMap
+		}
+		// confidenceSeries gathers the optimizer metrics, failing on duplicate
+		// series, and counts the recommendation confidence series
+		confidenceSeries := func(c client.Reader) int {
+			registry := prometheus.NewPedanticRegistry()
+			Expect(registry.Register(&optimizerCollector{client: c})).To(Succeed())
+			families, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			for _, family := range families {
+				if family.GetName() == "cost_optimizer_recommendation_confidence" {
+					return len(family.GetMetric())
+				}
+			}
+			return 0
+		}
+		ref := optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"}
+
+		It("should drop the targetRef status when switching to a selector", func() {
+			optimizer.Spec.TargetSelector = &optimizationv1.TargetSelector{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api-service"}},
+			}
+			patch := ownedPatch("api-service-optimizer-patch")
+			optimizer.Status = optimizationv1.ResourceOptimizerStatus{
+				CurrentRecommendation: recommendation.DeepCopy(),
+				RecommendationHistory: []optimizationv1.RecommendationRecord{{CPU: recommendation.CPU, Memory: recommendation.Memory}},
+				PatchConfigMap:        patch.Name,
+				Targets:               []optimizationv1.TargetStatus{{TargetRef: ref, Recommendation: recommendation.DeepCopy()}},
+			}
+			target := testDeployment(2, nil)
+			target.Labels = map[string]string{"app": "api-service"}
+			r := newTestReconciler(optimizer, target, patch)
+			Expect(confidenceSeries(r.Client)).To(Equal(1))
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			Expect(updated.Status.CurrentRecommendation).To(BeNil())
+			Expect(updated.Status.RecommendationHistory).To(BeEmpty())
+			Expect(updated.Status.PatchConfigMap).To(BeEmpty())
+			Expect(updated.Status.Targets).To(HaveLen(1))
+			err = r.Get(ctx, client.ObjectKeyFromObject(patch), &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(confidenceSeries(r.Client)).To(Equal(1))
+		})
+
+		It("should drop the selector status when switching to a targetRef", func() {
+			optimizer.Spec.TargetRef = ref.DeepCopy()
+			patch := ownedPatch("api-service-optimizer-api-service-patch")
+			optimizer.Status = optimizationv1.ResourceOptimizerStatus{
+				CurrentRecommendation: recommendation.DeepCopy(),
+				Targets: []optimizationv1.TargetStatus{{
+					TargetRef: ref, Recommendation: recommendation.DeepCopy(), PatchConfigMap: patch.Name,
+				}},
+			}
+			r := newTestReconciler(optimizer, testDeployment(2, nil), patch)
+			Expect(confidenceSeries(r.Client)).To(Equal(1))
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(BeEmpty())
+			Expect(updated.Status.CurrentRecommendation).NotTo(BeNil())
+			err = r.Get(ctx, client.ObjectKeyFromObject(patch), &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(confidenceSeries(r.Client)).To(Equal(1))
+		})
+	})
+
+	Context("When the target namespace of a targetRef does not grant access", func() {
+		var (
+			ctx       context.Context
+			optimizer *optimizationv1.ResourceOptimizer
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			optimizer = &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-service-optimizer", Namespace: "maintenance", Generation: 1},
+				Spec: optimizationv1.ResourceOptimizerSpec{
+					TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+					Policy: optimizationv1.Policy{
+						Cpu:    optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+						Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+					},
+				},
+				Status: optimizationv1.ResourceOptimizerStatus{
+					CurrentRecommendation: &optimizationv1.ResourceRecommendation{
+						CPU: optimizationv1.CPURecommendation{Request: "200m", Limit: "400m"},
+					},
+				},
+			}
+		})
+
+		It("should report the missing grant and drop the recommendation", func() {
+			r := newTestReconciler(optimizer, testDeployment(2, nil),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}})
+
+			result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			unauthorized := meta.FindStatusCondition(updated.Status.Conditions, "Unauthorized")
+			Expect(unauthorized).NotTo(BeNil())
+			Expect(unauthorized.Status).To(Equal(metav1.ConditionTrue))
+			Expect(unauthorized.Reason).To(Equal("GrantMissing"))
+			Expect(unauthorized.Message).To(ContainSubstring(optimizationv1.AnnotationAllowedSourceNamespaces))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, "OptimizationReady").Reason).To(Equal("Unauthorized"))
+			Expect(updated.Status.CurrentRecommendation).To(BeNil())
+			Expect(r.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("Warning Unauthorized")))
+		})
+
+		It("should treat a missing target namespace as not granted", func() {
+			r := newTestReconciler(optimizer)
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "Unauthorized")).To(BeTrue())
+		})
+
+		It("should proceed once the namespace grants access", func() {
+			r := newTestReconciler(optimizer, testDeployment(2, nil), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "production",
+				Annotations: map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "maintenance"},
+			}})
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			unauthorized := meta.FindStatusCondition(updated.Status.Conditions, "Unauthorized")
+			Expect(unauthorized).NotTo(BeNil())
+			Expect(unauthorized.Status).To(Equal(metav1.ConditionFalse))
+			Expect(unauthorized.Reason).To(Equal("Authorized"))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "DeploymentReady")).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// selectDeployments returns the Deployments matched by the target selector of
+// the ResourceOptimizer, searching its own namespace unless a namespace
+// selector is given. Results are listed namespace by namespace in name order.
+// Selected namespaces that do not grant access to the ResourceOptimizer are
+// not searched and returned separately.
+func (r *ResourceOptimizerReconciler) selectDeployments(ctx context.Context, resourceOptimizer *optimizationv1.ResourceOptimizer) ([]appsv1.Deployment, []string, error) {
+	targetSelector := resourceOptimizer.Spec.TargetSelector
+	if targetSelector.Kind != "" && targetSelector.Kind != "Deployment" {
+		return nil, nil, fmt.Errorf("unsupported target kind %q", targetSelector.Kind)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&targetSelector.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid target selector: %w", err)
+	}
+
+	namespaces := []string{resourceOptimizer.Namespace}
+	var unauthorized []string
+	if targetSelector.NamespaceSelector != nil {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(targetSelector.NamespaceSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid namespace selector: %w", err)
+		}
+		namespaceList := &corev1.NamespaceList{}
+		if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		namespaces = namespaces[:0]
+		for _, namespace := range namespaceList.Items {
+			if !optimizationv1.SourceNamespaceAllowed(namespace.Name, namespace.Annotations, resourceOptimizer.Namespace) {
+				unauthorized = append(unauthorized, namespace.Name)
+				continue
+			}
+			namespaces = append(namespaces, namespace.Name)
+		}
+		sort.Strings(namespaces)
+		sort.Strings(unauthorized)
+	}
+
+	var deployments []appsv1.Deployment
+	for _, namespace := range namespaces {
+		deploymentList := &appsv1.DeploymentList{}
+		if err := r.List(ctx, deploymentList, client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, nil, fmt.Errorf("failed to list Deployments in %s: %w", namespace, err)
+		}
+		sort.Slice(deploymentList.Items, func(i, j int) bool {
+			return deploymentList.Items[i].Name < deploymentList.Items[j].Name
+		})
+		deployments = append(deployments, deploymentList.Items...)
+	}
+
+	return deployments, unauthorized, nil
+}
+
+// explicitTargets maps every workload named in a targetRef to the
+// ResourceOptimizer naming it. An explicit targetRef takes precedence over a
+// target selector matching the same workload.
+func (r *ResourceOptimizerReconciler) explicitTargets(ctx context.Context) (map[optimizationv1.TargetRef]types.NamespacedName, error) {
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := r.List(ctx, optimizers); err != nil {
+		return nil, fmt.Errorf("failed to list ResourceOptimizers: %w", err)
+	}
+
+	targets := make(map[optimizationv1.TargetRef]types.NamespacedName)
+	for _, optimizer := range optimizers.Items {
+		if optimizer.Spec.TargetRef != nil {
+			targets[*optimizer.Spec.TargetRef] = client.ObjectKeyFromObject(&optimizer)
+		}
+	}
+	return targets, nil
+}
+
+// optimizerSelects reports whether the ResourceOptimizer manages the
+// deployment through its targetRef or targetSelector, and is allowed to by
+// namespace, the namespace of the deployment.
+func optimizerSelects(optimizer *optimizationv1.ResourceOptimizer, deployment *appsv1.Deployment, namespace *corev1.Namespace) (bool, error) {
+	if !optimizationv1.SourceNamespaceAllowed(namespace.Name, namespace.Annotations, optimizer.Namespace) {
+		return false, nil
+	}
+
+	if optimizer.Spec.TargetRef != nil {
+		target := *optimizer.Spec.TargetRef
+		return target.Kind == "Deployment" && target.Namespace == deployment.Namespace && target.Name == deployment.Name, nil
+	}
+
+	targetSelector := optimizer.Spec.TargetSelector
+	if targetSelector == nil || (targetSelector.Kind != "" && targetSelector.Kind != "Deployment") {
+		return false, nil
+	}
+	if targetSelector.NamespaceSelector == nil {
+		if deployment.Namespace != optimizer.Namespace {
+			return false, nil
+		}
+	} else if ok, err := selectorMatches(targetSelector.NamespaceSelector, labels.Set(namespace.Labels)); !ok || err != nil {
+		return false, err
+	}
+	return selectorMatches(&targetSelector.Selector, labels.Set(deployment.Labels))
+}
+
+// clusterOptimizerSelects reports whether the ClusterResourceOptimizer matches
+// the deployment. namespaceLabels are the labels of the deployment's namespace.
+func clusterOptimizerSelects(optimizer *optimizationv1.ClusterResourceOptimizer, deployment *appsv1.Deployment, namespaceLabels labels.Set) (bool, error) {
+	if optimizer.Spec.Kind != "" && optimizer.Spec.Kind != "Deployment" {
+		return false, nil
+	}
+	if ok, err := selectorMatches(&optimizer.Spec.NamespaceSelector, namespaceLabels); !ok || err != nil {
+		return false, err
+	}
+	return selectorMatches(&optimizer.Spec.Selector, labels.Set(deployment.Labels))
+}
+
+func selectorMatches(labelSelector *metav1.LabelSelector, set labels.Set) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(set), nil
+}
+
+// clusterOptimizerPrecedes reports whether a takes precedence over b for a
+// workload both match: higher priority first, then the oldest, then by name.
+func clusterOptimizerPrecedes(a, b *optimizationv1.ClusterResourceOptimizer) bool {
+	if a.Spec.Priority != b.Spec.Priority {
+		return a.Spec.Priority > b.Spec.Priority
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
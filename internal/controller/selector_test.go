@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Target selection", func() {
+	var ctx context.Context
+
+	// deployment returns a Deployment labeled tier=backend
+	deployment := func(name, namespace string) *appsv1.Deployment {
+		deployment := testDeployment(1, nil)
+		deployment.Name, deployment.Namespace = name, namespace
+		deployment.Labels = map[string]string{"tier": "backend"}
+		return deployment
+	}
+	namespace := func(name string, labels, annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations}}
+	}
+	selectorOptimizer := func(namespaceSelector *metav1.LabelSelector) *optimizationv1.ResourceOptimizer {
+		return &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "backend-optimizer", Namespace: "production"},
+			Spec: optimizationv1.ResourceOptimizerSpec{
+				TargetSelector: &optimizationv1.TargetSelector{
+					Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}},
+					NamespaceSelector: namespaceSelector,
+				},
+				Policy: optimizationv1.Policy{
+					Cpu:    optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+					Memory: optimizationv1.MemoryPolicy{BufferPercent: 20},
+				},
+			},
+		}
+	}
+	names := func(deployments []appsv1.Deployment) []string {
+		var names []string
+		for _, deployment := range deployments {
+			names = append(names, client.ObjectKeyFromObject(&deployment).String())
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Describe("selectDeployments", func() {
+		It("should search only the namespace of the optimizer without a namespace selector", func() {
+			unlabeled := deployment("worker", "production")
+			unlabeled.Labels = nil
+			r := newTestReconciler(deployment("web", "production"), deployment("api", "production"),
+				unlabeled, deployment("api", "staging"))
+
+			deployments, unauthorized, err := r.selectDeployments(ctx, selectorOptimizer(nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(deployments)).To(Equal([]string{"production/api", "production/web"}))
+			Expect(unauthorized).To(BeEmpty())
+		})
+
+		It("should search the selected namespaces that grant access", func() {
+			env := map[string]string{"env": "live"}
+			r := newTestReconciler(
+				namespace("production", env, nil),
+				namespace("payments", env, map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "production"}),
+				namespace("billing", env, nil),
+				namespace("staging", nil, map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "*"}),
+				deployment("api", "production"), deployment("ledger", "payments"), deployment("invoices", "billing"),
+				deployment("api", "staging"))
+
+			deployments, unauthorized, err := r.selectDeployments(ctx,
+				selectorOptimizer(&metav1.LabelSelector{MatchLabels: env}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(deployments)).To(Equal([]string{"payments/ledger", "production/api"}))
+			Expect(unauthorized).To(Equal([]string{"billing"}))
+		})
+
+		It("should reject an unsupported kind", func() {
+			optimizer := selectorOptimizer(nil)
+			optimizer.Spec.TargetSelector.Kind = "StatefulSet"
+			_, _, err := newTestReconciler().selectDeployments(ctx, optimizer)
+			Expect(err).To(MatchError(ContainSubstring("unsupported target kind")))
+		})
+
+		It("should reject an invalid selector", func() {
+			optimizer := selectorOptimizer(nil)
+			optimizer.Spec.TargetSelector.Selector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: "Matches", Values: []string{"backend"}},
+			}}
+			_, _, err := newTestReconciler().selectDeployments(ctx, optimizer)
+			Expect(err).To(MatchError(ContainSubstring("invalid target selector")))
+		})
+	})
+
+	Describe("explicitTargets", func() {
+		It("should map the workloads named in a targetRef to their optimizer", func() {
+			ref := optimizationv1.TargetRef{Kind: "Deployment", Name: "api", Namespace: "production"}
+			explicit := &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "maintenance"},
+				Spec:       optimizationv1.ResourceOptimizerSpec{TargetRef: &ref},
+			}
+			r := newTestReconciler(explicit, selectorOptimizer(nil))
+
+			targets, err := r.explicitTargets(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(targets).To(Equal(map[optimizationv1.TargetRef]types.NamespacedName{
+				ref: {Name: "api-optimizer", Namespace: "maintenance"},
+			}))
+		})
+	})
+
+	Describe("reconcileSelector", func() {
+		It("should carry the status of each target over from the last reconcile", func() {
+			recommendation := &optimizationv1.ResourceRecommendation{
+				CPU:    optimizationv1.CPURecommendation{Request: "200m", Limit: "400m"},
+				Memory: optimizationv1.MemoryRecommendation{Request: "256Mi", Limit: "512Mi"},
+				Reason: "Based on earlier usage",
+			}
+			history := []optimizationv1.RecommendationRecord{{
+				CPU:    recommendation.CPU,
+				Memory: recommendation.Memory,
+				Reason: recommendation.Reason,
+			}}
+			apiRef := optimizationv1.TargetRef{Kind: "Deployment", Name: "api", Namespace: "production"}
+			webRef := optimizationv1.TargetRef{Kind: "Deployment", Name: "web", Namespace: "production"}
+			goneRef := optimizationv1.TargetRef{Kind: "Deployment", Name: "removed", Namespace: "production"}
+
+			optimizer := selectorOptimizer(nil)
+			optimizer.Status.Targets = []optimizationv1.TargetStatus{
+				{TargetRef: apiRef, Recommendation: recommendation.DeepCopy(), History: history},
+				{TargetRef: webRef, Recommendation: recommendation.DeepCopy(), History: history},
+				{TargetRef: goneRef, Recommendation: recommendation.DeepCopy()},
+			}
+			explicit := &optimizationv1.ResourceOptimizer{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-optimizer", Namespace: "production"},
+				Spec:       optimizationv1.ResourceOptimizerSpec{TargetRef: &webRef},
+			}
+			r := newTestReconciler(optimizer, explicit, deployment("api", "production"), deployment("web", "production"))
+
+			_, err := r.reconcileSelector(ctx, optimizer)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &optimizationv1.ResourceOptimizer{}
+			Expect(r.Get(ctx, client.ObjectKeyFromObject(optimizer), updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(HaveLen(2))
+
+			By("keeping the last recommendation and history of a target without fresh metrics")
+			api := updated.Status.Targets[0]
+			Expect(api.TargetRef).To(Equal(apiRef))
+			Expect(api.Recommendation).NotTo(BeNil())
+			Expect(api.Recommendation.CPU).To(Equal(recommendation.CPU))
+			Expect(api.History).To(Equal(history))
+			Expect(meta.FindStatusCondition(api.Conditions, "OptimizationReady").Reason).To(Equal("NoMetricsData"))
+
+			By("clearing the recommendation but keeping the history of a superseded target")
+			web := updated.Status.Targets[1]
+			Expect(web.TargetRef).To(Equal(webRef))
+			Expect(web.Recommendation).To(BeNil())
+			Expect(web.History).To(Equal(history))
+			Expect(meta.FindStatusCondition(web.Conditions, "OptimizationReady").Reason).To(Equal("Superseded"))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// patchStatus writes the status of obj with a plain merge patch computed
+// against the object currently read from the client. The patch carries no
+// resourceVersion, so it never conflicts: only the status fields setStatus
+// changes are sent and the last writer wins for those, while fields another
+// writer updated in the meantime are left alone. Reading from a stale cache
+// therefore cannot make the write fail or loop; at worst it overwrites a newer
+// value of a field this reconcile computed as well.
+func patchStatus[T client.Object](ctx context.Context, c client.Client, obj T, setStatus func(latest T)) error {
+	latest := obj.DeepCopyObject().(T)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+		return err
+	}
+	base := latest.DeepCopyObject().(T)
+	setStatus(latest)
+	return c.Status().Patch(ctx, latest, client.MergeFrom(base))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Status patching", func() {
+	It("applies the status on top of a concurrently modified object", func() {
+		ctx := context.Background()
+		stored := &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "default"},
+			Status: optimizationv1.ResourceOptimizerStatus{
+				PatchConfigMap: "api-optimizer-patch",
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(stored).WithStatusSubresource(stored).Build()
+
+		stale := &optimizationv1.ResourceOptimizer{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(stored), stale)).To(Succeed())
+
+		// Another writer bumps the resourceVersion after our read.
+		concurrent := stale.DeepCopy()
+		concurrent.Labels = map[string]string{"team": "payments"}
+		Expect(c.Update(ctx, concurrent)).To(Succeed())
+
+		stale.Status.ObservedGeneration = 3
+		Expect(patchStatus(ctx, c, stale, func(latest *optimizationv1.ResourceOptimizer) {
+			latest.Status.ObservedGeneration = stale.Status.ObservedGeneration
+		})).To(Succeed())
+
+		updated := &optimizationv1.ResourceOptimizer{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(stored), updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(updated.Status.PatchConfigMap).To(Equal("api-optimizer-patch"))
+		Expect(updated.Labels).To(HaveKeyWithValue("team", "payments"))
+	})
+
+	It("writes the status when the cache lags behind the API server", func() {
+		ctx := context.Background()
+		stored := &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-optimizer", Namespace: "default"},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(stored).WithStatusSubresource(stored).Build()
+
+		// The cache still serves the object as it was before another writer
+		// updated its labels and status.
+		cached := &optimizationv1.ResourceOptimizer{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(stored), cached)).To(Succeed())
+		concurrent := cached.DeepCopy()
+		concurrent.Labels = map[string]string{"team": "payments"}
+		Expect(c.Update(ctx, concurrent)).To(Succeed())
+		concurrent.Status.PatchConfigMap = "api-optimizer-patch"
+		Expect(c.Status().Update(ctx, concurrent)).To(Succeed())
+
+		gets, patches := 0, 0
+		staleClient := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				cached.DeepCopyInto(obj.(*optimizationv1.ResourceOptimizer))
+				return nil
+			},
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		desired := cached.DeepCopy()
+		desired.Status.ObservedGeneration = 3
+		Expect(patchStatus(ctx, staleClient, desired, func(latest *optimizationv1.ResourceOptimizer) {
+			latest.Status = desired.Status
+		})).To(Succeed())
+		Expect(gets).To(Equal(1))
+		Expect(patches).To(Equal(1))
+
+		updated := &optimizationv1.ResourceOptimizer{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(stored), updated)).To(Succeed())
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(updated.Status.PatchConfigMap).To(Equal("api-optimizer-patch"),
+			"fields the write did not change keep the newer value")
+		Expect(updated.Labels).To(HaveKeyWithValue("team", "payments"))
+	})
+})
@@ -0,0 +1,224 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// targetIndexKey indexes ResourceOptimizers by the workloads they may target,
+// as "<kind>/<namespace>/<name>". Selectors index a wildcard name, and a
+// wildcard namespace as well when they carry a namespace selector.
+const targetIndexKey = ".spec.target"
+
+// targetWildcard stands for any namespace or name in a target index value.
+const targetWildcard = "*"
+
+func targetIndexValue(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// targetIndexValues is the IndexerFunc for targetIndexKey.
+func targetIndexValues(obj client.Object) []string {
+	optimizer, ok := obj.(*optimizationv1.ResourceOptimizer)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case optimizer.Spec.TargetRef != nil:
+		target := optimizer.Spec.TargetRef
+		return []string{targetIndexValue(target.Kind, target.Namespace, target.Name)}
+	case optimizer.Spec.TargetSelector != nil:
+		kind := optimizer.Spec.TargetSelector.Kind
+		if kind == "" {
+			kind = "Deployment"
+		}
+		if optimizer.Spec.TargetSelector.NamespaceSelector != nil {
+			return []string{targetIndexValue(kind, targetWildcard, targetWildcard)}
+		}
+		return []string{targetIndexValue(kind, optimizer.Namespace, targetWildcard)}
+	}
+	return nil
+}
+
+// requestsForDeployment maps a Deployment to the ResourceOptimizers targeting it.
+func (r *ResourceOptimizerReconciler) requestsForDeployment(ctx context.Context, obj client.Object) []reconcile.Request {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: deployment.Namespace}, namespace); err != nil {
+		log.Error(err, "Failed to get namespace", "namespace", deployment.Namespace)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, value := range []string{
+		targetIndexValue("Deployment", deployment.Namespace, deployment.Name),
+		targetIndexValue("Deployment", deployment.Namespace, targetWildcard),
+		targetIndexValue("Deployment", targetWildcard, targetWildcard),
+	} {
+		optimizers := &optimizationv1.ResourceOptimizerList{}
+		if err := r.List(ctx, optimizers, client.MatchingFields{targetIndexKey: value}); err != nil {
+			log.Error(err, "Failed to list ResourceOptimizers for Deployment", "deployment", client.ObjectKeyFromObject(deployment))
+			return nil
+		}
+
+		for i := range optimizers.Items {
+			optimizer := &optimizers.Items[i]
+			if selects, err := optimizerSelects(optimizer, deployment, namespace); err != nil || !selects {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+		}
+	}
+	return requests
+}
+
+// requestsForNamespace maps a Namespace to the ResourceOptimizers of other
+// namespaces that may target it, so granting or revoking access takes effect
+// without waiting for the periodic requeue.
+func (r *ResourceOptimizerReconciler) requestsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := r.List(ctx, optimizers); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ResourceOptimizers for namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range optimizers.Items {
+		optimizer := &optimizers.Items[i]
+		if optimizer.Namespace == obj.GetName() {
+			continue
+		}
+		targetRef, targetSelector := optimizer.Spec.TargetRef, optimizer.Spec.TargetSelector
+		if (targetRef != nil && targetRef.Namespace == obj.GetName()) ||
+			(targetSelector != nil && targetSelector.NamespaceSelector != nil) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(optimizer)})
+		}
+	}
+	return requests
+}
+
+// requestsForPod maps a Pod to the ResourceOptimizers targeting its Deployment
+// that are still waiting on its pods. Optimizers that already analyzed the
+// target pick pod changes up on their periodic requeue, so pod churn does not
+// trigger a full analysis each time.
+func (r *ResourceOptimizerReconciler) requestsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	deployment := owningDeployment(ctx, r.Client, obj)
+	if deployment == nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, request := range r.requestsForDeployment(ctx, deployment) {
+		optimizer := &optimizationv1.ResourceOptimizer{}
+		if err := r.Get(ctx, request.NamespacedName, optimizer); err != nil {
+			continue
+		}
+		if awaitingPods(targetConditions(optimizer, deployment)) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// targetConditions returns the conditions an optimizer reports for a
+// Deployment, or nil when it has not reported on it yet.
+func targetConditions(optimizer *optimizationv1.ResourceOptimizer, deployment *appsv1.Deployment) []metav1.Condition {
+	if optimizer.Spec.TargetSelector == nil {
+		return optimizer.Status.Conditions
+	}
+	for _, target := range optimizer.Status.Targets {
+		if target.Namespace == deployment.Namespace && target.Name == deployment.Name {
+			return target.Conditions
+		}
+	}
+	return nil
+}
+
+// awaitingPods reports whether a target has not been analyzed yet because its
+// Deployment was missing or its pods had no usable metrics.
+func awaitingPods(conditions []metav1.Condition) bool {
+	if meta.IsStatusConditionFalse(conditions, "DeploymentReady") {
+		return true
+	}
+	ready := meta.FindStatusCondition(conditions, "OptimizationReady")
+	if ready == nil {
+		return true
+	}
+	return ready.Status != metav1.ConditionTrue &&
+		(ready.Reason == "NoMetricsData" || ready.Reason == "WarmingUp")
+}
+
+// owningDeployment returns the Deployment a Pod belongs to, or nil.
+func owningDeployment(ctx context.Context, c client.Client, obj client.Object) *appsv1.Deployment {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	name := optimizationv1.OwningDeploymentName(pod)
+	if name == "" {
+		return nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, deployment); err != nil {
+		return nil
+	}
+	return deployment
+}
+
+// podReadinessChanged passes pod creations and deletions, but only those
+// updates that flip readiness, so routine status churn does not trigger
+// reconciles.
+var podReadinessChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, okOld := e.ObjectOld.(*corev1.Pod)
+		newPod, okNew := e.ObjectNew.(*corev1.Pod)
+		if !okOld || !okNew {
+			return false
+		}
+		return podReady(oldPod) != podReady(newPod)
+	},
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Target watches", func() {
+	var (
+		deployment *appsv1.Deployment
+		reconciler *ResourceOptimizerReconciler
+	)
+
+	optimizer := func(name string, spec optimizationv1.ResourceOptimizerSpec) *optimizationv1.ResourceOptimizer {
+		return &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "maintenance"},
+			Spec:       spec,
+		}
+	}
+
+	ownedPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-service-7d9f-x2k4q",
+				Namespace: "production",
+				Labels:    map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "7d9f"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "ReplicaSet",
+					Name:       "api-service-7d9f",
+					UID:        "replicaset-uid",
+					Controller: ptr.To(true),
+				}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-service",
+				Namespace: "production",
+				Labels:    map[string]string{"team": "payments"},
+			},
+		}
+		production := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "production",
+				Labels:      map[string]string{"env": "production"},
+				Annotations: map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "maintenance"},
+			},
+		}
+
+		objects := []*optimizationv1.ResourceOptimizer{
+			optimizer("by-name", optimizationv1.ResourceOptimizerSpec{
+				TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+			}),
+			optimizer("other-name", optimizationv1.ResourceOptimizerSpec{
+				TargetRef: &optimizationv1.TargetRef{Kind: "Deployment", Name: "web", Namespace: "production"},
+			}),
+			optimizer("by-team", optimizationv1.ResourceOptimizerSpec{
+				TargetSelector: &optimizationv1.TargetSelector{
+					Kind:              "Deployment",
+					Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "production"}},
+				},
+			}),
+			optimizer("other-team", optimizationv1.ResourceOptimizerSpec{
+				TargetSelector: &optimizationv1.TargetSelector{
+					Kind:              "Deployment",
+					Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"team": "search"}},
+					NamespaceSelector: &metav1.LabelSelector{},
+				},
+			}),
+			optimizer("own-namespace", optimizationv1.ResourceOptimizerSpec{
+				TargetSelector: &optimizationv1.TargetSelector{Kind: "Deployment"},
+			}),
+		}
+
+		builder := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithIndex(&optimizationv1.ResourceOptimizer{}, targetIndexKey, targetIndexValues).
+			WithObjects(deployment, production)
+		for _, object := range objects {
+			builder = builder.WithObjects(object)
+		}
+		reconciler = &ResourceOptimizerReconciler{Client: builder.Build()}
+	})
+
+	It("Should enqueue the optimizers selecting a Deployment", func() {
+		Expect(reconciler.requestsForDeployment(ctx, deployment)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "by-name", Namespace: "maintenance"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "by-team", Namespace: "maintenance"}},
+		))
+	})
+
+	It("Should ignore optimizers the target namespace does not allow", func() {
+		production := &corev1.Namespace{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "production"}, production)).To(Succeed())
+		production.Annotations = nil
+		Expect(reconciler.Update(ctx, production)).To(Succeed())
+
+		Expect(reconciler.requestsForDeployment(ctx, deployment)).To(BeEmpty())
+	})
+
+	It("Should enqueue the optimizers that may target a Namespace", func() {
+		production := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}}
+		Expect(reconciler.requestsForNamespace(ctx, production)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "by-name", Namespace: "maintenance"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "other-name", Namespace: "maintenance"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "by-team", Namespace: "maintenance"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "other-team", Namespace: "maintenance"}},
+		))
+	})
+
+	It("Should enqueue the optimizers of the Deployment owning a Pod", func() {
+		pod := ownedPod()
+		Expect(reconciler.requestsForPod(ctx, pod)).To(HaveLen(2))
+
+		pod.OwnerReferences = nil
+		Expect(reconciler.requestsForPod(ctx, pod)).To(BeEmpty())
+	})
+
+	It("Should only enqueue the optimizers waiting on the pods of a Deployment", func() {
+		byName := &optimizationv1.ResourceOptimizer{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "by-name", Namespace: "maintenance"}, byName)).To(Succeed())
+		byName.Status.Conditions = []metav1.Condition{
+			{Type: "DeploymentReady", Status: metav1.ConditionTrue, Reason: "TargetFound"},
+			{Type: "OptimizationReady", Status: metav1.ConditionTrue, Reason: "RecommendationGenerated"},
+		}
+		Expect(reconciler.Update(ctx, byName)).To(Succeed())
+
+		byTeam := &optimizationv1.ResourceOptimizer{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "by-team", Namespace: "maintenance"}, byTeam)).To(Succeed())
+		byTeam.Status.Targets = []optimizationv1.TargetStatus{{
+			TargetRef: optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+			Conditions: []metav1.Condition{
+				{Type: "OptimizationReady", Status: metav1.ConditionFalse, Reason: "NoMetricsData"},
+			},
+		}}
+		Expect(reconciler.Update(ctx, byTeam)).To(Succeed())
+
+		pod := ownedPod()
+		Expect(reconciler.requestsForPod(ctx, pod)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "by-team", Namespace: "maintenance"}},
+		))
+
+		// Once analyzed, the selector target no longer follows pod churn either.
+		byTeam.Status.Targets[0].Conditions = []metav1.Condition{
+			{Type: "OptimizationReady", Status: metav1.ConditionTrue, Reason: "RecommendationGenerated"},
+		}
+		Expect(reconciler.Update(ctx, byTeam)).To(Succeed())
+		Expect(reconciler.requestsForPod(ctx, pod)).To(BeEmpty())
+	})
+})
@@ -1,8 +1,10 @@
 package metrics
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -15,6 +17,31 @@ type Recommendation struct {
 	MemoryLimit   *resource.Quantity
 	Reason        string
 	Confidence    float64
+	Components    ConfidenceComponents
+	Explanation   Explanation
+
+	CurrentReplicas     int32
+	Replicas            *ReplicaRange
+	TargetShapeReplicas *ReplicaRange
+
+	// Peak per-pod usage of the samples left out as startup samples, nil if none were
+	StartupPeakCPU    *resource.Quantity
+	StartupPeakMemory *resource.Quantity
+}
+
+// ErrOnlyStartupSamples is returned when every sample was taken during the
+// startup grace period, e.g. right after a rollout.
+var ErrOnlyStartupSamples = errors.New("all samples were taken during the startup grace period")
+
+// ReplicaRange is the number of pods of a given shape needed to serve the
+// aggregate usage of the workload, from average (Min) to peak (Max) load.
+type ReplicaRange struct {
+	PodCPU      *resource.Quantity
+	PodMemory   *resource.Quantity
+	Min         int32
+	Max         int32
+	TotalCPU    *resource.Quantity
+	TotalMemory *resource.Quantity
 }
 
 type Analyzer struct{}
@@ -28,35 +55,136 @@ func (a *Analyzer) GenerateRecommendation(metrics *WorkloadMetrics, policy optim
 		return nil, fmt.Errorf("no usage data available")
 	}
 
+	// Leave out samples taken while containers were warming up
+	var startup []UsageData
+	if policy.StartupGracePeriod != nil && policy.StartupGracePeriod.Duration > 0 {
+		var steady []UsageData
+		steady, startup = splitStartupSamples(metrics.Usage, policy.StartupGracePeriod.Duration)
+		if len(steady) == 0 {
+			return nil, ErrOnlyStartupSamples
+		}
+		metrics = &WorkloadMetrics{Deployment: metrics.Deployment, Usage: steady, History: metrics.History}
+	}
+
+	// Replace values that stand out of their pod's recent usage
+	var grace time.Duration
+	if policy.StartupGracePeriod != nil {
+		grace = policy.StartupGracePeriod.Duration
+	}
+	metrics, outliers := a.rejectOutliers(metrics, grace)
+
 	// Calculate CPU recommendation
-	cpuRec, cpuReason := a.calculateCPURecommendation(metrics, policy.Cpu)
+	cpuRec, cpuReason, err := a.calculateCPURecommendation(metrics, policy.Cpu)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate Memory recommendation
 	memRec, memReason := a.calculateMemoryRecommendation(metrics, policy.Memory)
 
-	confidence := a.calculateConfidence(metrics)
+	// Startup spikes do not size the workload, but the memory limit must
+	// still let containers start without being OOM killed
+	var startupPeakCPU, startupPeakMemory *resource.Quantity
+	if len(startup) > 0 {
+		var peakCPU, peakMemory int64
+		for _, usage := range startup {
+			peakCPU = max(peakCPU, usage.CPUUsage.MilliValue())
+			peakMemory = max(peakMemory, usage.MemoryUsage.Value())
+		}
+		startupPeakCPU = resource.NewMilliQuantity(peakCPU, resource.DecimalSI)
+		startupPeakMemory = resource.NewQuantity(peakMemory, resource.BinarySI)
+		if startupPeakMemory.Cmp(*memRec.Limit) > 0 {
+			memRec.Limit = startupPeakMemory
+			memRec.Explanation.LimitBasis = LimitFromStartupPeak
+		}
+	}
+
+	// Round to the configured steps last, so the limits stay above the requests
+	if err := a.roundToSteps(cpuRec, memRec, policy); err != nil {
+		return nil, err
+	}
 
-	return &Recommendation{
-		CPURequest:    cpuRec.Request,
-		CPULimit:      cpuRec.Limit,
-		MemoryRequest: memRec.Request,
-		MemoryLimit:   memRec.Limit,
-		Reason:        fmt.Sprintf("CPU: %s, Memory: %s", cpuReason, memReason),
-		Confidence:    confidence,
-	}, nil
+	confidence, components := a.calculateConfidence(metrics)
+
+	recommendation := &Recommendation{
+		CPURequest:      cpuRec.Request,
+		CPULimit:        cpuRec.Limit,
+		MemoryRequest:   memRec.Request,
+		MemoryLimit:     memRec.Limit,
+		Reason:          fmt.Sprintf("CPU: %s, Memory: %s", cpuReason, memReason),
+		Confidence:      confidence,
+		Components:      components,
+		Explanation:     a.explain(metrics, len(startup), outliers, cpuRec.Explanation, memRec.Explanation),
+		CurrentReplicas: 1,
+
+		StartupPeakCPU:    startupPeakCPU,
+		StartupPeakMemory: startupPeakMemory,
+	}
+	if len(startup) > 0 {
+		recommendation.Reason += fmt.Sprintf("; %d startup samples left out (peak cpu=%s, memory=%s)",
+			len(startup), startupPeakCPU.String(), startupPeakMemory.String())
+	}
+	if outliers.cpu > 0 || outliers.memory > 0 {
+		recommendation.Reason += fmt.Sprintf("; outlier samples rejected (cpu=%d, memory=%d)", outliers.cpu, outliers.memory)
+	}
+	if metrics.Deployment != nil && metrics.Deployment.Spec.Replicas != nil {
+		recommendation.CurrentReplicas = *metrics.Deployment.Spec.Replicas
+	}
+
+	// Size the workload horizontally for the recommended pod shape and, if
+	// configured, for the alternative target shape
+	usage := a.aggregateUsage(metrics, grace)
+	recommendation.Replicas = a.calculateReplicas(usage, cpuRec.Request, memRec.Request, policy.Cpu.TargetUtilization, policy.Replicas)
+
+	if replicaPolicy := policy.Replicas; replicaPolicy != nil && (replicaPolicy.TargetPodCPU != "" || replicaPolicy.TargetPodMemory != "") {
+		podCPU, podMemory := cpuRec.Request, memRec.Request
+		if replicaPolicy.TargetPodCPU != "" {
+			quantity, err := resource.ParseQuantity(replicaPolicy.TargetPodCPU)
+			if err != nil {
+				return nil, fmt.Errorf("invalid target pod cpu %q: %w", replicaPolicy.TargetPodCPU, err)
+			}
+			podCPU = &quantity
+		}
+		if replicaPolicy.TargetPodMemory != "" {
+			quantity, err := resource.ParseQuantity(replicaPolicy.TargetPodMemory)
+			if err != nil {
+				return nil, fmt.Errorf("invalid target pod memory %q: %w", replicaPolicy.TargetPodMemory, err)
+			}
+			podMemory = &quantity
+		}
+		recommendation.TargetShapeReplicas = a.calculateReplicas(usage, podCPU, podMemory, policy.Cpu.TargetUtilization, replicaPolicy)
+	}
+
+	return recommendation, nil
+}
+
+// splitStartupSamples separates the samples taken within grace after their
+// pod's containers last started from the others. Samples without a known
+// start time count as steady.
+func splitStartupSamples(samples []UsageData, grace time.Duration) (steady, startup []UsageData) {
+	for _, sample := range samples {
+		if !sample.StartedAt.IsZero() && sample.Timestamp.Sub(sample.StartedAt) < grace {
+			startup = append(startup, sample)
+		} else {
+			steady = append(steady, sample)
+		}
+	}
+	return steady, startup
 }
 
 type cpuRecommendation struct {
-	Request *resource.Quantity
-	Limit   *resource.Quantity
+	Request     *resource.Quantity
+	Limit       *resource.Quantity
+	Explanation ResourceExplanation
 }
 
 type memoryRecommendation struct {
-	Request *resource.Quantity
-	Limit   *resource.Quantity
+	Request     *resource.Quantity
+	Limit       *resource.Quantity
+	Explanation ResourceExplanation
 }
 
-func (a *Analyzer) calculateCPURecommendation(metrics *WorkloadMetrics, policy optimizationv1.CPUPolicy) (*cpuRecommendation, string) {
+func (a *Analyzer) calculateCPURecommendation(metrics *WorkloadMetrics, policy optimizationv1.CPUPolicy) (*cpuRecommendation, string, error) {
 	// Get peak and average usage
 	var peak, total int64
 	for _, usage := range metrics.Usage {
@@ -72,28 +200,46 @@ func (a *Analyzer) calculateCPURecommendation(metrics *WorkloadMetrics, policy o
 	// Calculate target based on utilization policy
 	targetCPU := int64(float64(avg) / (float64(policy.TargetUtilization) / 100.0))
 
-	// Apply min/max constraints
-	minCPU := resource.MustParse(policy.Min)
-	maxCPU := resource.MustParse(policy.Max)
+	// Apply min/max constraints. The validating webhook rejects bad quantities,
+	// but the controller must not panic if one reaches it anyway.
+	minCPU, err := resource.ParseQuantity(policy.Min)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cpu min %q: %w", policy.Min, err)
+	}
+	maxCPU, err := resource.ParseQuantity(policy.Max)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid cpu max %q: %w", policy.Max, err)
+	}
 
+	explanation := ResourceExplanation{
+		Target:     *resource.NewMilliQuantity(targetCPU, resource.DecimalSI),
+		LimitBasis: LimitFromRequest,
+	}
 	if targetCPU < minCPU.MilliValue() {
 		targetCPU = minCPU.MilliValue()
+		explanation.ClampedBy = append(explanation.ClampedBy, BoundMin)
 	}
 	if targetCPU > maxCPU.MilliValue() {
 		targetCPU = maxCPU.MilliValue()
+		explanation.ClampedBy = append(explanation.ClampedBy, BoundMax)
 	}
 
 	// Set request to target, limit to 1.5x target (with peak consideration)
 	request := resource.NewMilliQuantity(targetCPU, resource.DecimalSI)
-	limitValue := int64(math.Max(float64(targetCPU)*1.5, float64(peak)*1.1))
+	limitValue := int64(float64(targetCPU) * 1.5)
+	if peakLimit := int64(float64(peak) * 1.1); peakLimit > limitValue {
+		limitValue = peakLimit
+		explanation.LimitBasis = LimitFromPeak
+	}
 	limit := resource.NewMilliQuantity(limitValue, resource.DecimalSI)
 
 	reason := fmt.Sprintf("avg=%dm, peak=%dm, target=%dm", avg, peak, targetCPU)
 
 	return &cpuRecommendation{
-		Request: request,
-		Limit:   limit,
-	}, reason
+		Request:     request,
+		Limit:       limit,
+		Explanation: explanation,
+	}, reason, nil
 }
 
 func (a *Analyzer) calculateMemoryRecommendation(metrics *WorkloadMetrics, policy optimizationv1.MemoryPolicy) (*memoryRecommendation, string) {
@@ -124,56 +270,166 @@ func (a *Analyzer) calculateMemoryRecommendation(metrics *WorkloadMetrics, polic
 	return &memoryRecommendation{
 		Request: request,
 		Limit:   limit,
+		Explanation: ResourceExplanation{
+			Target:     *resource.NewQuantity(targetMemory, resource.BinarySI),
+			LimitBasis: LimitFromRequest,
+		},
 	}, reason
 }
 
-func (a *Analyzer) calculateConfidence(metrics *WorkloadMetrics) float64 {
-	// Confidence based on data points and variance
-	dataPoints := len(metrics.Usage)
-
-	if dataPoints < 5 {
-		return 0.3 // Low confidence with few data points
+// roundToSteps rounds the recommended requests and limits up to the steps of
+// the policy. The CPU request is then held to the steps within the min and
+// max of the policy: a minimum between steps rounds up to the next step and a
+// maximum between steps rounds down to the previous one. When no step falls
+// within the bounds, the request is held to the bounds themselves.
+func (a *Analyzer) roundToSteps(cpuRec *cpuRecommendation, memRec *memoryRecommendation, policy optimizationv1.Policy) error {
+	if policy.Cpu.Step != "" {
+		step, err := resource.ParseQuantity(policy.Cpu.Step)
+		if err != nil {
+			return fmt.Errorf("invalid cpu step %q: %w", policy.Cpu.Step, err)
+		}
+		minCPU, err := resource.ParseQuantity(policy.Cpu.Min)
+		if err != nil {
+			return fmt.Errorf("invalid cpu min %q: %w", policy.Cpu.Min, err)
+		}
+		maxCPU, err := resource.ParseQuantity(policy.Cpu.Max)
+		if err != nil {
+			return fmt.Errorf("invalid cpu max %q: %w", policy.Cpu.Max, err)
+		}
+		if step := step.MilliValue(); step > 0 {
+			lowest, highest := roundUp(minCPU.MilliValue(), step), maxCPU.MilliValue()-maxCPU.MilliValue()%step
+			if lowest > highest {
+				lowest, highest = minCPU.MilliValue(), maxCPU.MilliValue()
+			}
+			request := min(max(roundUp(cpuRec.Request.MilliValue(), step), lowest), highest)
+			if request != cpuRec.Request.MilliValue() {
+				cpuRec.Explanation.ClampedBy = append(cpuRec.Explanation.ClampedBy, BoundStep)
+			}
+			cpuRec.Request = resource.NewMilliQuantity(request, resource.DecimalSI)
+			cpuRec.Limit = resource.NewMilliQuantity(max(roundUp(cpuRec.Limit.MilliValue(), step), request), resource.DecimalSI)
+		}
 	}
-	if dataPoints < 20 {
-		return 0.6 // Medium confidence
+
+	if policy.Memory.Step != "" {
+		step, err := resource.ParseQuantity(policy.Memory.Step)
+		if err != nil {
+			return fmt.Errorf("invalid memory step %q: %w", policy.Memory.Step, err)
+		}
+		if step := step.Value(); step > 0 {
+			request := roundUp(memRec.Request.Value(), step)
+			if request != memRec.Request.Value() {
+				memRec.Explanation.ClampedBy = append(memRec.Explanation.ClampedBy, BoundStep)
+			}
+			memRec.Request = resource.NewQuantity(request, resource.BinarySI)
+			memRec.Limit = resource.NewQuantity(roundUp(memRec.Limit.Value(), step), resource.BinarySI)
+		}
 	}
+	return nil
+}
 
-	// Calculate variance for additional confidence scoring
-	var cpuValues []float64
-	for _, usage := range metrics.Usage {
-		cpuValues = append(cpuValues, float64(usage.CPUUsage.MilliValue()))
+// roundUp rounds value up to a multiple of step.
+func roundUp(value, step int64) int64 {
+	if remainder := value % step; remainder != 0 {
+		return value + step - remainder
 	}
+	return value
+}
 
-	variance := a.calculateVariance(cpuValues)
+// workloadUsage is the usage of all pods of a workload combined.
+type workloadUsage struct {
+	avgCPU     int64 // millicores
+	peakCPU    int64 // millicores
+	avgMemory  int64 // bytes
+	peakMemory int64 // bytes
+}
 
-	// Lower variance = higher confidence
-	if variance < 100 { // Low variance in CPU usage
-		return 0.9
+// aggregateUsage sums the usage of all pods per collection and returns the
+// average and the peak of these totals over the history, so the replica
+// range follows how the load of the workload varied. The current samples,
+// already filtered for outliers, replace their copies in the history.
+// Samples taken within grace of a container start are left out.
+func (a *Analyzer) aggregateUsage(metrics *WorkloadMetrics, grace time.Duration) workloadUsage {
+	samples := append([]UsageData(nil), metrics.Usage...)
+	seen := make(map[sampleKey]bool, len(metrics.Usage))
+	for _, sample := range metrics.Usage {
+		seen[sampleKey{pod: sample.Pod, at: sample.Timestamp.UnixNano()}] = true
+	}
+	for _, sample := range metrics.History {
+		if !seen[sampleKey{pod: sample.Pod, at: sample.Timestamp.UnixNano()}] {
+			samples = append(samples, sample)
+		}
 	}
-	if variance < 500 {
-		return 0.7
+	if grace > 0 {
+		if steady, _ := splitStartupSamples(samples, grace); len(steady) > 0 {
+			samples = steady
+		}
 	}
 
-	return 0.5 // Medium confidence for high variance
-}
+	type total struct{ cpu, memory int64 }
+	collections := map[time.Time]*total{}
+	for _, sample := range samples {
+		collection, ok := collections[sample.collection()]
+		if !ok {
+			collection = &total{}
+			collections[sample.collection()] = collection
+		}
+		collection.cpu += sample.CPUUsage.MilliValue()
+		collection.memory += sample.MemoryUsage.Value()
+	}
 
-func (a *Analyzer) calculateVariance(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
+	var aggregate workloadUsage
+	if len(collections) == 0 {
+		return aggregate
+	}
+	for _, collection := range collections {
+		aggregate.avgCPU += collection.cpu
+		aggregate.avgMemory += collection.memory
+		aggregate.peakCPU = max(aggregate.peakCPU, collection.cpu)
+		aggregate.peakMemory = max(aggregate.peakMemory, collection.memory)
 	}
+	aggregate.avgCPU /= int64(len(collections))
+	aggregate.avgMemory /= int64(len(collections))
+	return aggregate
+}
+
+// calculateReplicas returns how many pods with the given requests are needed
+// to serve the aggregate usage: CPU at the target utilization, memory in full.
+func (a *Analyzer) calculateReplicas(usage workloadUsage, podCPU, podMemory *resource.Quantity, targetUtilization int32, policy *optimizationv1.ReplicaPolicy) *ReplicaRange {
+	cpuCapacity := float64(podCPU.MilliValue()) * float64(targetUtilization) / 100.0
+	memoryCapacity := float64(podMemory.Value())
 
-	// Calculate mean
-	var sum float64
-	for _, v := range values {
-		sum += v
+	replicasFor := func(cpu, memory int64) int32 {
+		var replicas float64
+		if cpuCapacity > 0 {
+			replicas = math.Ceil(float64(cpu) / cpuCapacity)
+		}
+		if memoryCapacity > 0 {
+			replicas = math.Max(replicas, math.Ceil(float64(memory)/memoryCapacity))
+		}
+		return int32(replicas)
 	}
-	mean := sum / float64(len(values))
 
-	// Calculate variance
-	var variance float64
-	for _, v := range values {
-		variance += math.Pow(v-mean, 2)
+	minReplicas := replicasFor(usage.avgCPU, usage.avgMemory)
+	maxReplicas := replicasFor(usage.peakCPU, usage.peakMemory)
+
+	lower, upper := int32(1), int32(0)
+	if policy != nil {
+		lower = max(lower, policy.MinReplicas)
+		upper = policy.MaxReplicas
+	}
+	minReplicas = max(minReplicas, lower)
+	maxReplicas = max(maxReplicas, minReplicas)
+	if upper > 0 {
+		minReplicas = min(minReplicas, upper)
+		maxReplicas = min(maxReplicas, upper)
 	}
 
-	return variance / float64(len(values))
+	return &ReplicaRange{
+		PodCPU:      podCPU,
+		PodMemory:   podMemory,
+		Min:         minReplicas,
+		Max:         maxReplicas,
+		TotalCPU:    resource.NewMilliQuantity(podCPU.MilliValue()*int64(minReplicas), resource.DecimalSI),
+		TotalMemory: resource.NewQuantity(podMemory.Value()*int64(minReplicas), resource.BinarySI),
+	}
 }
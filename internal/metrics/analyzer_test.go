@@ -0,0 +1,222 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// sample builds a usage data point for a pod.
+func sample(pod, cpu, memory string, at time.Time) UsageData {
+	return UsageData{
+		Pod:         pod,
+		CPUUsage:    resource.MustParse(cpu),
+		MemoryUsage: resource.MustParse(memory),
+		Timestamp:   at,
+	}
+}
+
+var _ = Describe("Analyzer", func() {
+	var (
+		analyzer *Analyzer
+		policy   optimizationv1.Policy
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		analyzer = NewAnalyzer()
+		now = time.Now()
+		policy = optimizationv1.Policy{
+			Cpu:    optimizationv1.CPUPolicy{Min: "50m", Max: "2", TargetUtilization: 50},
+			Memory: optimizationv1.MemoryPolicy{BufferPercent: 0},
+		}
+	})
+
+	Context("When the CPU policy holds an invalid quantity", func() {
+		It("Should return an error instead of panicking", func() {
+			policy.Cpu.Min = "lots"
+			metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "100m", "100Mi", now)}}
+
+			_, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).To(MatchError(ContainSubstring("invalid cpu min")))
+		})
+	})
+
+	Context("When recommending replicas", func() {
+		var metrics *WorkloadMetrics
+
+		BeforeEach(func() {
+			metrics = &WorkloadMetrics{
+				Deployment: &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](4)}},
+				Usage: []UsageData{
+					sample("a", "100m", "100Mi", now),
+					sample("b", "100m", "100Mi", now),
+					sample("c", "100m", "100Mi", now),
+					sample("d", "100m", "100Mi", now),
+				},
+			}
+		})
+
+		It("Should size the recommended pod shape from aggregate usage", func() {
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Each pod is recommended 200m at 50% utilization, i.e. serves 100m.
+			Expect(recommendation.CurrentReplicas).To(Equal(int32(4)))
+			Expect(recommendation.Replicas.PodCPU.String()).To(Equal("200m"))
+			Expect(recommendation.Replicas.Min).To(Equal(int32(4)))
+			Expect(recommendation.Replicas.TotalCPU.String()).To(Equal("800m"))
+			Expect(recommendation.TargetShapeReplicas).To(BeNil())
+		})
+
+		It("Should compare against the target pod shape", func() {
+			policy.Replicas = &optimizationv1.ReplicaPolicy{TargetPodCPU: "400m", TargetPodMemory: "200Mi"}
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recommendation.TargetShapeReplicas.Min).To(Equal(int32(2)))
+			Expect(recommendation.TargetShapeReplicas.TotalCPU.String()).To(Equal("800m"))
+			Expect(recommendation.TargetShapeReplicas.TotalMemory.String()).To(Equal("400Mi"))
+		})
+
+		It("Should size the range from the usage of every collection in the history", func() {
+			// The workload used 400m, 400m and 1.2 cores in total over three collections
+			for i, cpu := range []string{"100m", "100m", "300m"} {
+				at := now.Add(time.Duration(i-2) * 5 * time.Minute)
+				for _, pod := range []string{"a", "b", "c", "d"} {
+					metrics.History = append(metrics.History, sample(pod, cpu, "100Mi", at))
+				}
+			}
+			metrics.Usage = metrics.History[:4]
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Pods of 200m serve 100m each: 667m on average and 1.2 cores at the peak
+			Expect(recommendation.Replicas.PodCPU.String()).To(Equal("200m"))
+			Expect(recommendation.Replicas.Min).To(Equal(int32(7)))
+			Expect(recommendation.Replicas.Max).To(Equal(int32(12)))
+		})
+
+		It("Should honor the replica bounds", func() {
+			policy.Replicas = &optimizationv1.ReplicaPolicy{MinReplicas: 1, MaxReplicas: 3}
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recommendation.Replicas.Min).To(Equal(int32(3)))
+			Expect(recommendation.Replicas.Max).To(Equal(int32(3)))
+		})
+	})
+
+	Context("When a startup grace period is set", func() {
+		var metrics *WorkloadMetrics
+
+		BeforeEach(func() {
+			policy.StartupGracePeriod = &metav1.Duration{Duration: 5 * time.Minute}
+			warmingUp := sample("b", "4", "900Mi", now)
+			warmingUp.StartedAt = now.Add(-time.Minute)
+			steady := sample("a", "200m", "500Mi", now)
+			steady.StartedAt = now.Add(-time.Hour)
+			metrics = &WorkloadMetrics{Usage: []UsageData{steady, warmingUp}}
+		})
+
+		It("Should size the workload on steady samples and report the startup peak", func() {
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recommendation.CPURequest.String()).To(Equal("400m"))
+			Expect(recommendation.CPULimit.String()).To(Equal("600m"))
+			Expect(recommendation.MemoryRequest.String()).To(Equal("500Mi"))
+			Expect(recommendation.StartupPeakCPU.String()).To(Equal("4"))
+			Expect(recommendation.MemoryLimit.String()).To(Equal("900Mi"))
+			Expect(recommendation.Reason).To(ContainSubstring("1 startup samples left out"))
+		})
+
+		It("Should not recommend anything while all pods are warming up", func() {
+			metrics.Usage = metrics.Usage[1:]
+			_, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).To(MatchError(ErrOnlyStartupSamples))
+		})
+	})
+
+	Context("When rounding steps are set", func() {
+		BeforeEach(func() {
+			policy.Cpu.Step = "25m"
+			policy.Memory.Step = "64Mi"
+		})
+
+		It("Should round requests and limits up to the steps", func() {
+			metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "173m", "187654321", now)}}
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			// 346m at 50% utilization with a 519m limit, 187654321 bytes with a 225185185 limit
+			Expect(recommendation.CPURequest.String()).To(Equal("350m"))
+			Expect(recommendation.CPULimit.String()).To(Equal("525m"))
+			Expect(recommendation.MemoryRequest.String()).To(Equal("192Mi"))
+			Expect(recommendation.MemoryLimit.String()).To(Equal("256Mi"))
+			Expect(recommendation.Explanation.CPU.ClampedBy).To(Equal([]string{BoundStep}))
+			Expect(recommendation.Explanation.Memory.ClampedBy).To(Equal([]string{BoundStep}))
+		})
+
+		It("Should round the CPU request down to a step below the maximum", func() {
+			policy.Cpu.Max = "340m"
+			policy.Cpu.Step = "100m"
+			metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "173m", "128Mi", now)}}
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recommendation.CPURequest.String()).To(Equal("300m"))
+			Expect(recommendation.CPULimit.String()).To(Equal("600m"))
+			Expect(recommendation.MemoryRequest.String()).To(Equal("128Mi"))
+			Expect(recommendation.Explanation.CPU.ClampedBy).To(Equal([]string{BoundMax, BoundStep}))
+			Expect(recommendation.Explanation.Memory.ClampedBy).To(BeEmpty())
+		})
+
+		It("Should keep the CPU request at a maximum below one step", func() {
+			policy.Cpu.Min = "10m"
+			policy.Cpu.Max = "80m"
+			policy.Cpu.Step = "100m"
+			metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "173m", "128Mi", now)}}
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recommendation.CPURequest.String()).To(Equal("80m"))
+			Expect(recommendation.Explanation.CPU.ClampedBy).To(Equal([]string{BoundMax}))
+		})
+
+		It("Should round a minimum between steps up to the next step", func() {
+			policy.Cpu.Min = "250m"
+			policy.Cpu.Step = "100m"
+			metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "20m", "128Mi", now)}}
+
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recommendation.CPURequest.String()).To(Equal("300m"))
+			Expect(recommendation.Explanation.CPU.ClampedBy).To(Equal([]string{BoundMin, BoundStep}))
+		})
+
+		It("Should keep the CPU request within bounds that hold no step", func() {
+			policy.Cpu.Min = "250m"
+			policy.Cpu.Max = "350m"
+			policy.Cpu.Step = "200m"
+			metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "173m", "128Mi", now)}}
+
+			// 346m rounds up to 400m, above the maximum, and down to 200m, below the minimum
+			recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recommendation.CPURequest.String()).To(Equal("350m"))
+			Expect(recommendation.CPURequest.Cmp(resource.MustParse(policy.Cpu.Min))).To(BeNumerically(">=", 0))
+		})
+	})
+})
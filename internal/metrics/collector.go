@@ -3,6 +3,8 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -11,28 +13,78 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/metrics/pkg/client/clientset/versioned"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
 )
 
 type UsageData struct {
+	Pod         string
 	CPUUsage    resource.Quantity
 	MemoryUsage resource.Quantity
 	Timestamp   time.Time
+
+	// StartedAt is the most recent start of a managed container of the pod,
+	// zero if unknown
+	StartedAt time.Time
+
+	// CollectedAt is when the collection the sample belongs to ran. Unlike
+	// Timestamp, which metrics-server sets per pod, it is shared by all
+	// samples of a collection.
+	CollectedAt time.Time
+}
+
+// collection returns the time of the collection the sample belongs to, or
+// its timestamp to the second when that is unknown.
+func (u UsageData) collection() time.Time {
+	if !u.CollectedAt.IsZero() {
+		return u.CollectedAt
+	}
+	return u.Timestamp.Truncate(time.Second)
 }
 
 type WorkloadMetrics struct {
 	Deployment *appsv1.Deployment
 	Usage      []UsageData
+
+	// History holds the samples of earlier collections within the retention
+	// period, including the current ones, oldest first
+	History []UsageData
 }
 
+// DefaultHistoryRetention is how long collected samples are kept in memory.
+const DefaultHistoryRetention = 7 * 24 * time.Hour
+
+// Bounds of the in-memory history of a workload. A pod keeps at most one
+// sample every five minutes over the retention period, and a workload at most
+// defaultMaxHistorySamples samples, so churning pods cannot grow it unbounded.
+const (
+	defaultMaxPodSamples     = int(DefaultHistoryRetention / (5 * time.Minute))
+	defaultMaxHistorySamples = 20000
+)
+
 type Collector struct {
 	kubeClient    kubernetes.Interface
 	metricsClient versioned.Interface
+
+	// Samples per workload, keyed by namespace/name. The history is lost on
+	// restart and rebuilt by subsequent collections.
+	mu        sync.Mutex
+	history   map[string][]UsageData
+	retention time.Duration
+
+	// maxPodSamples and maxSamples bound the history per pod and per workload
+	maxPodSamples int
+	maxSamples    int
 }
 
 func NewCollector(kubeClient kubernetes.Interface, metricsClient versioned.Interface) *Collector {
 	return &Collector{
 		kubeClient:    kubeClient,
 		metricsClient: metricsClient,
+		history:       map[string][]UsageData{},
+		retention:     DefaultHistoryRetention,
+		maxPodSamples: defaultMaxPodSamples,
+		maxSamples:    defaultMaxHistorySamples,
 	}
 }
 
@@ -47,19 +99,36 @@ func (c *Collector) CollectWorkloadMetrics(ctx context.Context, deployment *apps
 		return nil, fmt.Errorf("failed to get pod metrics: %w", err)
 	}
 
-	var totalCPU, totalMemory resource.Quantity
+	startTimes, err := c.containerStartTimes(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+
 	var usageData []UsageData
 
+	now := time.Now()
 	for _, podMetric := range podMetrics.Items {
+		// Usage is reported per pod, summed over its managed containers
+		var totalCPU, totalMemory resource.Quantity
 		for _, container := range podMetric.Containers {
+			if optimizationv1.ContainerExcluded(deployment.Annotations, container.Name) {
+				continue
+			}
 			totalCPU.Add(container.Usage["cpu"])
 			totalMemory.Add(container.Usage["memory"])
 		}
 
+		timestamp := podMetric.Timestamp.Time
+		if timestamp.IsZero() {
+			timestamp = now
+		}
 		usageData = append(usageData, UsageData{
+			Pod:         podMetric.Name,
 			CPUUsage:    totalCPU,
 			MemoryUsage: totalMemory,
-			Timestamp:   podMetric.Timestamp.Time,
+			Timestamp:   timestamp,
+			StartedAt:   startTimes[podMetric.Name],
+			CollectedAt: now,
 		})
 
 		log.V(1).Info("Collected metrics",
@@ -71,9 +140,117 @@ func (c *Collector) CollectWorkloadMetrics(ctx context.Context, deployment *apps
 	return &WorkloadMetrics{
 		Deployment: deployment,
 		Usage:      usageData,
+		History:    c.record(deployment.Namespace+"/"+deployment.Name, usageData, now),
 	}, nil
 }
 
+// containerStartTimes returns the most recent start of a managed container
+// per pod of the deployment, so samples taken during warm-up can be told
+// apart. Without a Kubernetes client no start times are known.
+func (c *Collector) containerStartTimes(ctx context.Context, deployment *appsv1.Deployment) (map[string]time.Time, error) {
+	startTimes := map[string]time.Time{}
+	if c.kubeClient == nil {
+		return startTimes, nil
+	}
+
+	pods, err := c.kubeClient.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(deployment.Spec.Selector),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Running == nil || optimizationv1.ContainerExcluded(deployment.Annotations, status.Name) {
+				continue
+			}
+			if startedAt := status.State.Running.StartedAt.Time; startedAt.After(startTimes[pod.Name]) {
+				startTimes[pod.Name] = startedAt
+			}
+		}
+	}
+	return startTimes, nil
+}
+
+// record adds the samples to the history of the workload, skipping samples
+// of a pod already recorded for the same timestamp, and returns a copy of
+// the history. Samples older than the retention period are dropped, as are
+// workloads without recent samples. The history is then bounded, see bound.
+func (c *Collector) record(key string, samples []UsageData, now time.Time) []UsageData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := now.Add(-c.retention)
+	history := c.history[key]
+	recorded := make(map[sampleKey]bool, len(history)+len(samples))
+	for _, sample := range history {
+		recorded[sampleKey{pod: sample.Pod, at: sample.Timestamp.UnixNano()}] = true
+	}
+	for _, sample := range samples {
+		id := sampleKey{pod: sample.Pod, at: sample.Timestamp.UnixNano()}
+		if !recorded[id] {
+			recorded[id] = true
+			history = append(history, sample)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Timestamp.Before(history[j].Timestamp) })
+	first := sort.Search(len(history), func(i int) bool { return !history[i].Timestamp.Before(cutoff) })
+	c.history[key] = c.bound(history[first:], samples)
+
+	for other, samples := range c.history {
+		if len(samples) == 0 || samples[len(samples)-1].Timestamp.Before(cutoff) {
+			delete(c.history, other)
+		}
+	}
+
+	return append([]UsageData(nil), c.history[key]...)
+}
+
+// bound drops the oldest samples of pods above maxPodSamples, then the oldest
+// samples of the workload above maxSamples. Pods missing from the current
+// samples are gone, so their samples are dropped before those of live pods.
+func (c *Collector) bound(history, current []UsageData) []UsageData {
+	live := map[string]bool{}
+	for _, sample := range current {
+		live[sample.Pod] = true
+	}
+	perPod := map[string]int{}
+	for _, sample := range history {
+		perPod[sample.Pod]++
+	}
+
+	excessPerPod := map[string]int{}
+	kept, keptGone := 0, 0
+	for pod, count := range perPod {
+		excessPerPod[pod] = max(count-c.maxPodSamples, 0)
+		kept += count - excessPerPod[pod]
+		if !live[pod] {
+			keptGone += count - excessPerPod[pod]
+		}
+	}
+	excess := max(kept-c.maxSamples, 0)
+	if excess == 0 && kept == len(history) {
+		return history
+	}
+	excessGone := min(excess, keptGone)
+	excessLive := excess - excessGone
+
+	bounded := make([]UsageData, 0, kept-excess)
+	for _, sample := range history {
+		switch {
+		case excessPerPod[sample.Pod] > 0:
+			excessPerPod[sample.Pod]--
+		case !live[sample.Pod] && excessGone > 0:
+			excessGone--
+		case live[sample.Pod] && excessLive > 0:
+			excessLive--
+		default:
+			bounded = append(bounded, sample)
+		}
+	}
+	return bounded
+}
+
 func (c *Collector) GetAverageUsage(metrics *WorkloadMetrics, duration time.Duration) (cpuAvg, memoryAvg resource.Quantity) {
 	if len(metrics.Usage) == 0 {
 		return
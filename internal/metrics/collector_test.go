@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Collector", func() {
+	var (
+		collector  *Collector
+		deployment *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-service", Namespace: "production"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api-service"}},
+			},
+		}
+
+		podMetrics := &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-service-7d9f-x2k4q",
+				Namespace: "production",
+				Labels:    map[string]string{"app": "api-service"},
+			},
+			Containers: []metricsv1beta1.ContainerMetrics{
+				{Name: "api", Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("200m"),
+					corev1.ResourceMemory: resource.MustParse("256Mi"),
+				}},
+				{Name: "sidecar", Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("50m"),
+					corev1.ResourceMemory: resource.MustParse("64Mi"),
+				}},
+			},
+		}
+		// The fake tracker files PodMetrics under a guessed resource name, so
+		// serve the list directly.
+		metricsClient := metricsfake.NewSimpleClientset()
+		metricsClient.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{*podMetrics}}, nil
+		})
+		collector = NewCollector(nil, metricsClient)
+	})
+
+	It("Should sum the usage of all containers of a pod", func() {
+		workloadMetrics, err := collector.CollectWorkloadMetrics(context.Background(), deployment)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workloadMetrics.Usage).To(HaveLen(1))
+		Expect(workloadMetrics.Usage[0].CPUUsage.String()).To(Equal("250m"))
+		Expect(workloadMetrics.Usage[0].MemoryUsage.String()).To(Equal("320Mi"))
+	})
+
+	It("Should leave out containers excluded by annotation", func() {
+		deployment.Annotations = map[string]string{optimizationv1.AnnotationExcludeContainers: "sidecar"}
+
+		workloadMetrics, err := collector.CollectWorkloadMetrics(context.Background(), deployment)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workloadMetrics.Usage[0].CPUUsage.String()).To(Equal("200m"))
+		Expect(workloadMetrics.Usage[0].MemoryUsage.String()).To(Equal("256Mi"))
+	})
+
+	Context("When recording the history", func() {
+		now := time.Now()
+		collection := func(at time.Time, pods ...string) []UsageData {
+			samples := make([]UsageData, 0, len(pods))
+			for _, pod := range pods {
+				samples = append(samples, sample(pod, "100m", "100Mi", at))
+			}
+			return samples
+		}
+
+		It("Should skip samples recorded before and drop expired ones", func() {
+			collector.record("production/api-service", collection(now.Add(-8*24*time.Hour), "a"), now.Add(-8*24*time.Hour))
+			collector.record("production/api-service", collection(now.Add(-time.Minute), "a", "b"), now.Add(-time.Minute))
+
+			history := collector.record("production/api-service", collection(now.Add(-time.Minute), "a", "b"), now)
+			Expect(history).To(HaveLen(2))
+		})
+
+		It("Should keep the newest samples of a pod", func() {
+			collector.maxPodSamples = 3
+			var history []UsageData
+			for i := range 5 {
+				at := now.Add(time.Duration(i-5) * time.Minute)
+				history = collector.record("production/api-service", collection(at, "a", "b"), at)
+			}
+
+			Expect(history).To(HaveLen(6))
+			Expect(history[0].Timestamp).To(Equal(now.Add(-3 * time.Minute)))
+		})
+
+		It("Should drop the samples of gone pods first", func() {
+			collector.maxSamples = 4
+			for i := range 3 {
+				at := now.Add(time.Duration(i-10) * time.Minute)
+				collector.record("production/api-service", collection(at, "old"), at)
+			}
+			collector.record("production/api-service", collection(now.Add(-2*time.Minute), "new"), now)
+			history := collector.record("production/api-service", collection(now.Add(-time.Minute), "new"), now)
+
+			pods := map[string]int{}
+			for _, sample := range history {
+				pods[sample.Pod]++
+			}
+			Expect(pods).To(Equal(map[string]int{"old": 2, "new": 2}))
+			Expect(history[0].Timestamp).To(Equal(now.Add(-9 * time.Minute)))
+		})
+	})
+})
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Parameters of the confidence model. A recommendation reaches full
+// confidence once the history covers confidenceWindow, usage varies little,
+// at least fullConfidencePods pods were observed and no collections were
+// missed. An interval longer than gapFactor times the usual interval between
+// collections counts as a gap.
+const (
+	confidenceWindow   = 24 * time.Hour
+	fullConfidencePods = 3
+	gapFactor          = 2
+)
+
+// ConfidenceComponents are the inputs of the confidence of a recommendation.
+type ConfidenceComponents struct {
+	// HistoryDuration is the time span between the first and the last
+	// collection
+	HistoryDuration time.Duration
+
+	// Coverage is HistoryDuration as a fraction of confidenceWindow, at most 1
+	Coverage float64
+
+	// CPUVariation and MemoryVariation are the coefficients of variation
+	// (standard deviation over mean) of the per-pod usage samples
+	CPUVariation    float64
+	MemoryVariation float64
+
+	// Pods is the number of distinct pods observed
+	Pods int
+
+	// Gaps is the fraction of HistoryDuration in which collections were missed
+	Gaps float64
+}
+
+// calculateConfidence scores how far a recommendation can be trusted, from 0
+// to 1, as the product of four factors:
+//
+//   - coverage of the confidence window, so a day of history is needed
+//   - 1/(1+v) with v the larger coefficient of variation of CPU and memory,
+//     which unlike raw variance does not depend on the size of the workload
+//   - 0.7 plus 0.1 per observed pod, up to 1 from fullConfidencePods pods on
+//   - one minus the fraction of the history lost in gaps
+func (a *Analyzer) calculateConfidence(metrics *WorkloadMetrics) (float64, ConfidenceComponents) {
+	samples := metrics.History
+	if len(samples) == 0 {
+		samples = metrics.Usage
+	}
+	components := confidenceComponents(samples)
+
+	stability := 1 / (1 + math.Max(components.CPUVariation, components.MemoryVariation))
+	pods := math.Min(1, 0.7+0.1*float64(components.Pods))
+	confidence := components.Coverage * stability * pods * (1 - components.Gaps)
+	return math.Round(confidence*100) / 100, components
+}
+
+// confidenceComponents measures the samples the confidence is based on.
+func confidenceComponents(samples []UsageData) ConfidenceComponents {
+	var components ConfidenceComponents
+	if len(samples) == 0 {
+		return components
+	}
+
+	pods := map[string]bool{}
+	cpu := make([]float64, 0, len(samples))
+	memory := make([]float64, 0, len(samples))
+	collections := map[time.Time]bool{}
+	for _, sample := range samples {
+		pods[sample.Pod] = true
+		cpu = append(cpu, float64(sample.CPUUsage.MilliValue()))
+		memory = append(memory, float64(sample.MemoryUsage.Value()))
+		collections[sample.collection()] = true
+	}
+	components.Pods = len(pods)
+	components.CPUVariation = coefficientOfVariation(cpu)
+	components.MemoryVariation = coefficientOfVariation(memory)
+
+	timestamps := make([]time.Time, 0, len(collections))
+	for timestamp := range collections {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+	components.HistoryDuration = timestamps[len(timestamps)-1].Sub(timestamps[0])
+	if components.HistoryDuration <= 0 {
+		return components
+	}
+	components.Coverage = math.Min(1, components.HistoryDuration.Seconds()/confidenceWindow.Seconds())
+
+	// Time beyond the usual interval between collections was not observed
+	intervals := make([]float64, 0, len(timestamps)-1)
+	for i := 1; i < len(timestamps); i++ {
+		intervals = append(intervals, timestamps[i].Sub(timestamps[i-1]).Seconds())
+	}
+	usual := medianOf(intervals)
+	var missing float64
+	for _, interval := range intervals {
+		if interval > gapFactor*usual {
+			missing += interval - usual
+		}
+	}
+	components.Gaps = missing / components.HistoryDuration.Seconds()
+	return components
+}
+
+// coefficientOfVariation returns the population standard deviation of values
+// relative to their mean, or 0 if the mean is 0.
+func coefficientOfVariation(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(squares/float64(len(values))) / mean
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Confidence", func() {
+	var (
+		analyzer *Analyzer
+		now      time.Time
+	)
+
+	// history collects usage of the given pods every 30 minutes over a day,
+	// alternating between low and high usage, scaled by factor.
+	history := func(pods int, factor int, skip func(time.Duration) bool) []UsageData {
+		var samples []UsageData
+		for at := time.Duration(0); at <= 24*time.Hour; at += 30 * time.Minute {
+			if skip != nil && skip(at) {
+				continue
+			}
+			cpu, memory := 80*factor, 200*factor
+			if at/(30*time.Minute)%2 == 1 {
+				cpu, memory = 120*factor, 300*factor
+			}
+			for pod := range pods {
+				samples = append(samples, sample(fmt.Sprintf("pod-%d", pod),
+					fmt.Sprintf("%dm", cpu), fmt.Sprintf("%dMi", memory), now.Add(at-24*time.Hour)))
+			}
+		}
+		return samples
+	}
+
+	BeforeEach(func() {
+		analyzer = NewAnalyzer()
+		now = time.Now().Truncate(time.Second)
+	})
+
+	It("Should be confident about a day of steady usage of several pods", func() {
+		confidence, components := analyzer.calculateConfidence(&WorkloadMetrics{History: history(3, 1, nil)})
+
+		Expect(components.HistoryDuration).To(Equal(24 * time.Hour))
+		Expect(components.Coverage).To(Equal(1.0))
+		Expect(components.Pods).To(Equal(3))
+		Expect(components.Gaps).To(BeZero())
+		Expect(components.CPUVariation).To(BeNumerically("~", 0.2, 0.01))
+		Expect(components.MemoryVariation).To(BeNumerically("~", 0.2, 0.01))
+		Expect(confidence).To(Equal(0.83))
+	})
+
+	It("Should not depend on the size of the workload", func() {
+		small, _ := analyzer.calculateConfidence(&WorkloadMetrics{History: history(3, 1, nil)})
+		large, _ := analyzer.calculateConfidence(&WorkloadMetrics{History: history(3, 20, nil)})
+		Expect(large).To(Equal(small))
+	})
+
+	It("Should discount single pods, short histories and gaps", func() {
+		full, _ := analyzer.calculateConfidence(&WorkloadMetrics{History: history(3, 1, nil)})
+
+		single, _ := analyzer.calculateConfidence(&WorkloadMetrics{History: history(1, 1, nil)})
+		Expect(single).To(BeNumerically("<", full))
+
+		short, components := analyzer.calculateConfidence(&WorkloadMetrics{History: history(3, 1, func(at time.Duration) bool {
+			return at < 18*time.Hour
+		})})
+		Expect(components.Coverage).To(Equal(0.25))
+		Expect(short).To(BeNumerically("<", full))
+
+		gappy, components := analyzer.calculateConfidence(&WorkloadMetrics{History: history(3, 1, func(at time.Duration) bool {
+			return at > 6*time.Hour && at < 12*time.Hour
+		})})
+		Expect(components.Gaps).To(BeNumerically("~", 0.23, 0.01))
+		Expect(gappy).To(BeNumerically("<", full))
+	})
+
+	It("Should tell collections apart by collection time, not pod timestamps", func() {
+		// metrics-server scrapes pods a few seconds apart
+		samples := history(3, 1, nil)
+		for i := range samples {
+			samples[i].CollectedAt = samples[i].Timestamp
+			samples[i].Timestamp = samples[i].Timestamp.Add(time.Duration(i%3) * 7 * time.Second)
+		}
+
+		_, components := analyzer.calculateConfidence(&WorkloadMetrics{History: samples})
+		Expect(components.HistoryDuration).To(Equal(24 * time.Hour))
+		Expect(components.Gaps).To(BeZero())
+	})
+
+	It("Should have no confidence in a single collection", func() {
+		confidence, components := analyzer.calculateConfidence(&WorkloadMetrics{
+			Usage: []UsageData{sample("a", "100m", "100Mi", now), sample("b", "100m", "100Mi", now)},
+		})
+		Expect(components.Pods).To(Equal(2))
+		Expect(confidence).To(BeZero())
+	})
+})
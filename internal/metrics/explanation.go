@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Bounds that can change a recommended request.
+const (
+	BoundMin  = "min"
+	BoundMax  = "max"
+	BoundStep = "step"
+)
+
+// What a recommended limit was derived from.
+const (
+	// LimitFromRequest is a fixed headroom over the recommended request
+	LimitFromRequest = "request"
+	// LimitFromPeak is a headroom over the peak usage, when that is higher
+	LimitFromPeak = "peak"
+	// LimitFromStartupPeak is the peak usage during the startup grace period
+	LimitFromStartupPeak = "startupPeak"
+)
+
+// Explanation records how a recommendation was computed, so it can be
+// rendered and asserted on without parsing the reason.
+type Explanation struct {
+	// Window is the time span between the first and the last sample analyzed
+	Window time.Duration
+
+	// Samples is the number of samples analyzed and Pods the number of pods
+	// they were taken from
+	Samples int
+	Pods    int
+
+	// StartupSamples is the number of samples left out as startup samples
+	StartupSamples int
+
+	CPU    ResourceExplanation
+	Memory ResourceExplanation
+}
+
+// ResourceExplanation describes the recommendation of one resource.
+type ResourceExplanation struct {
+	// Statistics of the per-pod usage samples
+	Average resource.Quantity
+	Peak    resource.Quantity
+	P50     resource.Quantity
+	P90     resource.Quantity
+	P99     resource.Quantity
+
+	// OutliersRejected is the number of values replaced as outliers
+	OutliersRejected int
+
+	// Target is the request derived from usage and policy before any bound
+	// was applied
+	Target resource.Quantity
+
+	// ClampedBy lists the bounds that changed the request, in the order they
+	// were applied
+	ClampedBy []string
+
+	// LimitBasis is what the limit was derived from
+	LimitBasis string
+}
+
+// explain completes the explanations of the CPU and memory recommendations
+// with the statistics of the analyzed samples.
+func (a *Analyzer) explain(metrics *WorkloadMetrics, startupSamples int, outliers outlierReport, cpu, memory ResourceExplanation) Explanation {
+	explanation := Explanation{
+		Samples:        len(metrics.Usage),
+		StartupSamples: startupSamples,
+		CPU:            cpu,
+		Memory:         memory,
+	}
+
+	pods := map[string]bool{}
+	cpuValues := make([]int64, 0, len(metrics.Usage))
+	memoryValues := make([]int64, 0, len(metrics.Usage))
+	var first, last time.Time
+	for _, sample := range metrics.Usage {
+		pods[sample.Pod] = true
+		cpuValues = append(cpuValues, sample.CPUUsage.MilliValue())
+		memoryValues = append(memoryValues, sample.MemoryUsage.Value())
+		if first.IsZero() || sample.Timestamp.Before(first) {
+			first = sample.Timestamp
+		}
+		if sample.Timestamp.After(last) {
+			last = sample.Timestamp
+		}
+	}
+	explanation.Pods = len(pods)
+	explanation.Window = last.Sub(first)
+
+	explanation.CPU.OutliersRejected = outliers.cpu
+	explanation.CPU.Average, explanation.CPU.Peak, explanation.CPU.P50, explanation.CPU.P90, explanation.CPU.P99 =
+		usageStatistics(cpuValues, func(value int64) resource.Quantity {
+			return *resource.NewMilliQuantity(value, resource.DecimalSI)
+		})
+	explanation.Memory.OutliersRejected = outliers.memory
+	explanation.Memory.Average, explanation.Memory.Peak, explanation.Memory.P50, explanation.Memory.P90, explanation.Memory.P99 =
+		usageStatistics(memoryValues, func(value int64) resource.Quantity {
+			return *resource.NewQuantity(value, resource.BinarySI)
+		})
+	return explanation
+}
+
+// usageStatistics returns the average, peak and nearest-rank percentiles of
+// values as quantities.
+func usageStatistics(values []int64, quantity func(int64) resource.Quantity) (average, peak, p50, p90, p99 resource.Quantity) {
+	if len(values) == 0 {
+		zero := quantity(0)
+		return zero, zero, zero, zero, zero
+	}
+
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total int64
+	for _, value := range sorted {
+		total += value
+	}
+	percentile := func(p float64) resource.Quantity {
+		rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return quantity(sorted[max(rank, 0)])
+	}
+	return quantity(total / int64(len(sorted))), quantity(sorted[len(sorted)-1]),
+		percentile(0.5), percentile(0.9), percentile(0.99)
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Explanation", func() {
+	var (
+		analyzer *Analyzer
+		policy   optimizationv1.Policy
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		analyzer = NewAnalyzer()
+		now = time.Now()
+		policy = optimizationv1.Policy{
+			Cpu:    optimizationv1.CPUPolicy{Min: "50m", Max: "300m", TargetUtilization: 50},
+			Memory: optimizationv1.MemoryPolicy{BufferPercent: 10},
+		}
+	})
+
+	It("Should describe the samples, the statistics and the bounds applied", func() {
+		metrics := &WorkloadMetrics{Usage: []UsageData{
+			sample("a", "100m", "100Mi", now.Add(-2*time.Minute)),
+			sample("b", "120m", "200Mi", now.Add(-time.Minute)),
+			sample("a", "140m", "300Mi", now.Add(-time.Minute)),
+			sample("b", "500m", "400Mi", now),
+		}}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+
+		explanation := recommendation.Explanation
+		Expect(explanation.Window).To(Equal(2 * time.Minute))
+		Expect(explanation.Samples).To(Equal(4))
+		Expect(explanation.Pods).To(Equal(2))
+
+		// avg 215m at 50% utilization asks for 430m, which max brings down to 300m;
+		// 1.1x the 500m peak is above 1.5x the request
+		Expect(explanation.CPU.Average.String()).To(Equal("215m"))
+		Expect(explanation.CPU.Peak.String()).To(Equal("500m"))
+		Expect(explanation.CPU.P50.String()).To(Equal("120m"))
+		Expect(explanation.CPU.P90.String()).To(Equal("500m"))
+		Expect(explanation.CPU.Target.String()).To(Equal("430m"))
+		Expect(explanation.CPU.ClampedBy).To(Equal([]string{BoundMax}))
+		Expect(explanation.CPU.LimitBasis).To(Equal(LimitFromPeak))
+		Expect(recommendation.CPURequest.String()).To(Equal("300m"))
+
+		Expect(explanation.Memory.Average.String()).To(Equal("250Mi"))
+		Expect(explanation.Memory.Peak.String()).To(Equal("400Mi"))
+		Expect(explanation.Memory.ClampedBy).To(BeEmpty())
+		Expect(explanation.Memory.LimitBasis).To(Equal(LimitFromRequest))
+	})
+
+	It("Should record startup samples and a limit raised to their peak", func() {
+		policy.StartupGracePeriod = &metav1.Duration{Duration: 5 * time.Minute}
+		warmingUp := sample("b", "1", "2Gi", now)
+		warmingUp.StartedAt = now.Add(-time.Minute)
+		metrics := &WorkloadMetrics{Usage: []UsageData{sample("a", "100m", "100Mi", now), warmingUp}}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.Explanation.Samples).To(Equal(1))
+		Expect(recommendation.Explanation.StartupSamples).To(Equal(1))
+		Expect(recommendation.Explanation.Memory.LimitBasis).To(Equal(LimitFromStartupPeak))
+	})
+})
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// IdleResult is the outcome of idle detection over the usage history.
+type IdleResult struct {
+	// Idle is set when the per-pod CPU usage stayed at or below the threshold
+	// for the whole period
+	Idle bool
+
+	// Observed is how far back the history reaches, at most the period
+	Observed time.Duration
+
+	// PeakCPU is the highest per-pod CPU usage within the period
+	PeakCPU resource.Quantity
+}
+
+// DetectIdle checks whether the workload used next to no CPU over the period
+// ending at now. A workload is only reported idle once the history covers the
+// whole period, so a restart of the operator does not produce false positives.
+func (a *Analyzer) DetectIdle(metrics *WorkloadMetrics, period time.Duration, threshold resource.Quantity, now time.Time) IdleResult {
+	var result IdleResult
+	if len(metrics.History) == 0 {
+		return result
+	}
+
+	start := now.Add(-period)
+	result.Observed = min(now.Sub(metrics.History[0].Timestamp), period)
+
+	var peak int64
+	for _, sample := range metrics.History {
+		if sample.Timestamp.Before(start) {
+			continue
+		}
+		peak = max(peak, sample.CPUUsage.MilliValue())
+	}
+	result.PeakCPU = *resource.NewMilliQuantity(peak, resource.DecimalSI)
+	result.Idle = result.Observed >= period && peak <= threshold.MilliValue()
+	return result
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("Idle detection", func() {
+	var (
+		analyzer  *Analyzer
+		now       time.Time
+		threshold resource.Quantity
+	)
+
+	BeforeEach(func() {
+		analyzer = NewAnalyzer()
+		now = time.Now()
+		threshold = resource.MustParse("5m")
+	})
+
+	It("Should report a workload idle once the history covers the period", func() {
+		metrics := &WorkloadMetrics{History: []UsageData{
+			sample("a", "2m", "50Mi", now.Add(-25*time.Hour)),
+			sample("a", "1m", "50Mi", now.Add(-12*time.Hour)),
+			sample("a", "3m", "50Mi", now),
+		}}
+
+		result := analyzer.DetectIdle(metrics, 24*time.Hour, threshold, now)
+		Expect(result.Idle).To(BeTrue())
+		Expect(result.PeakCPU.String()).To(Equal("3m"))
+	})
+
+	It("Should not report a workload idle before the period is observed", func() {
+		metrics := &WorkloadMetrics{History: []UsageData{
+			sample("a", "1m", "50Mi", now.Add(-2*time.Hour)),
+			sample("a", "1m", "50Mi", now),
+		}}
+
+		result := analyzer.DetectIdle(metrics, 24*time.Hour, threshold, now)
+		Expect(result.Idle).To(BeFalse())
+		Expect(result.Observed).To(Equal(2 * time.Hour))
+	})
+
+	It("Should ignore usage from before the period", func() {
+		metrics := &WorkloadMetrics{History: []UsageData{
+			sample("a", "800m", "50Mi", now.Add(-30*time.Hour)),
+			sample("a", "1m", "50Mi", now.Add(-6*time.Hour)),
+			sample("a", "10m", "50Mi", now),
+		}}
+
+		Expect(analyzer.DetectIdle(metrics, 24*time.Hour, threshold, now).Idle).To(BeFalse())
+		Expect(analyzer.DetectIdle(metrics, 24*time.Hour, resource.MustParse("10m"), now).Idle).To(BeTrue())
+	})
+})
+
+var _ = Describe("Usage history", func() {
+	It("Should keep distinct samples within the retention period", func() {
+		collector := NewCollector(nil, nil)
+		now := time.Now()
+
+		collector.record("production/api-service", []UsageData{sample("a", "10m", "50Mi", now.Add(-8*24*time.Hour))}, now)
+		collector.record("production/api-service", []UsageData{sample("a", "20m", "50Mi", now.Add(-time.Hour))}, now)
+		history := collector.record("production/api-service", []UsageData{
+			sample("a", "20m", "50Mi", now.Add(-time.Hour)),
+			sample("a", "30m", "50Mi", now),
+		}, now)
+
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].CPUUsage.String()).To(Equal("20m"))
+		Expect(history[1].CPUUsage.String()).To(Equal("30m"))
+	})
+})
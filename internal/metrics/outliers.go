@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Hampel filter parameters. A value is an outlier when it lies more than
+// hampelThreshold scaled median absolute deviations from the median of the
+// 2*hampelHalfWindow+1 samples of the same pod closest to it in time. Windows
+// with fewer than minHampelSamples samples are too short to judge and left
+// alone.
+const (
+	hampelHalfWindow = 3
+	hampelThreshold  = 3.0
+	minHampelSamples = 5
+
+	// madScale turns the median absolute deviation into an estimate of the
+	// standard deviation for normally distributed data
+	madScale = 1.4826
+
+	// minRelativeScale is the smallest deviation scale as a fraction of the
+	// median, so a spike still stands out of otherwise constant usage
+	minRelativeScale = 0.05
+)
+
+// outlierReport counts the values replaced by the Hampel filter.
+type outlierReport struct {
+	cpu    int
+	memory int
+}
+
+// sampleKey identifies a sample of a pod across collections.
+type sampleKey struct {
+	pod string
+	at  int64
+}
+
+// rejectOutliers runs a Hampel filter over each pod's usage series and
+// replaces outlying CPU and memory values of the current samples by the
+// median of their window. A single bad metrics-server sample, e.g. during a
+// node hiccup, then no longer sets the peak the recommendation is sized on.
+// The history of earlier collections is used as reference when available.
+// Samples taken within grace of a container start are not used as reference.
+//
+// The current samples are the newest of their series and can only be judged
+// on older ones, so a real increase looks like an outlier until later samples
+// confirm it. Memory values are therefore only replaced when that raises them:
+// lowering memory below what a live pod uses right now would get it OOM
+// killed, while too little CPU only throttles it until the history catches up.
+func (a *Analyzer) rejectOutliers(metrics *WorkloadMetrics, grace time.Duration) (*WorkloadMetrics, outlierReport) {
+	series := metrics.History
+	if len(series) == 0 {
+		series = metrics.Usage
+	}
+	if grace > 0 {
+		series, _ = splitStartupSamples(series, grace)
+	}
+
+	pods := map[string][]UsageData{}
+	for _, sample := range series {
+		pods[sample.Pod] = append(pods[sample.Pod], sample)
+	}
+
+	cpuMedians := map[sampleKey]int64{}
+	memoryMedians := map[sampleKey]int64{}
+	for pod, samples := range pods {
+		if len(samples) < minHampelSamples {
+			continue
+		}
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+		cpu := make([]float64, len(samples))
+		memory := make([]float64, len(samples))
+		for i, sample := range samples {
+			cpu[i] = float64(sample.CPUUsage.MilliValue())
+			memory[i] = float64(sample.MemoryUsage.Value())
+		}
+		for i, sample := range samples {
+			key := sampleKey{pod: pod, at: sample.Timestamp.UnixNano()}
+			if median, outlier := hampel(cpu, i); outlier {
+				cpuMedians[key] = int64(median)
+			}
+			if median, outlier := hampel(memory, i); outlier {
+				memoryMedians[key] = int64(median)
+			}
+		}
+	}
+	if len(cpuMedians) == 0 && len(memoryMedians) == 0 {
+		return metrics, outlierReport{}
+	}
+
+	var report outlierReport
+	usage := make([]UsageData, len(metrics.Usage))
+	for i, sample := range metrics.Usage {
+		key := sampleKey{pod: sample.Pod, at: sample.Timestamp.UnixNano()}
+		if median, ok := cpuMedians[key]; ok {
+			sample.CPUUsage = *resource.NewMilliQuantity(median, resource.DecimalSI)
+			report.cpu++
+		}
+		if median, ok := memoryMedians[key]; ok && median > sample.MemoryUsage.Value() {
+			sample.MemoryUsage = *resource.NewQuantity(median, resource.BinarySI)
+			report.memory++
+		}
+		usage[i] = sample
+	}
+	return &WorkloadMetrics{Deployment: metrics.Deployment, Usage: usage, History: metrics.History}, report
+}
+
+// hampel reports whether values[i] is an outlier within its window, and the
+// median of the window it should be replaced by. The window is shifted at
+// the ends of the series so the newest sample is judged on its predecessors.
+func hampel(values []float64, i int) (float64, bool) {
+	size := min(2*hampelHalfWindow+1, len(values))
+	if size < minHampelSamples {
+		return 0, false
+	}
+	start := min(max(i-hampelHalfWindow, 0), len(values)-size)
+	window := values[start : start+size]
+
+	median := medianOf(window)
+	deviations := make([]float64, len(window))
+	for j, value := range window {
+		deviations[j] = math.Abs(value - median)
+	}
+	scale := max(madScale*medianOf(deviations), minRelativeScale*median)
+	if scale == 0 {
+		return 0, false
+	}
+	return median, math.Abs(values[i]-median) > hampelThreshold*scale
+}
+
+// medianOf returns the median of values without modifying them.
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Outlier rejection", func() {
+	var (
+		analyzer *Analyzer
+		policy   optimizationv1.Policy
+		now      time.Time
+		history  []UsageData
+	)
+
+	BeforeEach(func() {
+		analyzer = NewAnalyzer()
+		now = time.Now()
+		policy = optimizationv1.Policy{
+			Cpu:    optimizationv1.CPUPolicy{Min: "50m", Max: "2", TargetUtilization: 50},
+			Memory: optimizationv1.MemoryPolicy{BufferPercent: 0},
+		}
+		memory := []string{"500Mi", "510Mi", "505Mi", "495Mi", "500Mi", "505Mi"}
+		history = nil
+		for i, value := range memory {
+			history = append(history, sample("a", "100m", value, now.Add(time.Duration(i-len(memory))*time.Minute)))
+		}
+	})
+
+	It("Should replace a single CPU spike by the median of its window", func() {
+		spike := sample("a", "2", "505Mi", now)
+		metrics := &WorkloadMetrics{Usage: []UsageData{spike}, History: append(history, spike)}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.CPURequest.String()).To(Equal("200m"))
+		Expect(recommendation.Reason).To(ContainSubstring("outlier samples rejected (cpu=1, memory=0)"))
+	})
+
+	It("Should replace a memory dip by the median of its window", func() {
+		dip := sample("a", "100m", "50Mi", now)
+		metrics := &WorkloadMetrics{Usage: []UsageData{dip}, History: append(history, dip)}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.MemoryRequest.String()).To(Equal("500Mi"))
+		Expect(recommendation.Reason).To(ContainSubstring("outlier samples rejected (cpu=0, memory=1)"))
+	})
+
+	It("Should never lower the memory a live pod currently uses", func() {
+		increase := sample("a", "100m", "4Gi", now)
+		metrics := &WorkloadMetrics{Usage: []UsageData{increase}, History: append(history, increase)}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.MemoryRequest.String()).To(Equal("4Gi"))
+		Expect(recommendation.Reason).NotTo(ContainSubstring("outlier"))
+	})
+
+	It("Should keep usage that is in line with the history", func() {
+		current := sample("a", "110m", "520Mi", now)
+		metrics := &WorkloadMetrics{Usage: []UsageData{current}, History: append(history, current)}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.MemoryRequest.String()).To(Equal("520Mi"))
+		Expect(recommendation.Reason).NotTo(ContainSubstring("outlier"))
+	})
+
+	It("Should leave short series alone", func() {
+		spike := sample("a", "100m", "4Gi", now)
+		metrics := &WorkloadMetrics{Usage: []UsageData{spike}, History: append(history[4:], spike)}
+
+		recommendation, err := analyzer.GenerateRecommendation(metrics, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recommendation.MemoryRequest.String()).To(Equal("4Gi"))
+	})
+})
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Thresholds of memory growth detection. A drop of more than restartDrop
+// starts a new segment, as a container restart or a full GC would; a segment
+// counts as growing when it spans at least minGrowthSamples samples over
+// minGrowthDuration, at least minIncreasingSteps of its steps do not
+// decrease, and it ends at least minGrowthRatio above where it started.
+const (
+	restartDrop        = 0.10
+	minGrowthSamples   = 6
+	minGrowthDuration  = time.Hour
+	minIncreasingSteps = 0.8
+	minGrowthRatio     = 1.2
+)
+
+// GrowthResult describes the steepest memory growth found in the history.
+type GrowthResult struct {
+	// Detected is set when a pod's memory grew steadily since its last restart
+	Detected bool
+
+	// Pod with the steepest growth
+	Pod string
+
+	// From and To are the memory usage at the start and end of the growing segment
+	From resource.Quantity
+	To   resource.Quantity
+
+	// Duration of the growing segment
+	Duration time.Duration
+
+	// PerHour is the average growth rate of the segment
+	PerHour resource.Quantity
+}
+
+// DetectMemoryGrowth looks for pods whose memory usage keeps growing since
+// their last restart, which points to a leak rather than a workload needing
+// more headroom. Only the latest segment of each pod is considered, so a leak
+// that was fixed by a rollout is no longer reported. Samples taken within
+// grace of a container start are left out, as memory rising while a runtime
+// warms up is not a leak.
+func (a *Analyzer) DetectMemoryGrowth(metrics *WorkloadMetrics, grace time.Duration) GrowthResult {
+	history := metrics.History
+	if grace > 0 {
+		history, _ = splitStartupSamples(history, grace)
+	}
+
+	pods := map[string][]UsageData{}
+	for _, sample := range history {
+		pods[sample.Pod] = append(pods[sample.Pod], sample)
+	}
+
+	var result GrowthResult
+	var steepest float64
+	for pod, samples := range pods {
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+		// Keep the segment since the last drop
+		start := 0
+		for i := 1; i < len(samples); i++ {
+			if float64(samples[i].MemoryUsage.Value()) < float64(samples[i-1].MemoryUsage.Value())*(1-restartDrop) {
+				start = i
+			}
+		}
+		segment := samples[start:]
+		if len(segment) < minGrowthSamples {
+			continue
+		}
+		first, last := segment[0], segment[len(segment)-1]
+		duration := last.Timestamp.Sub(first.Timestamp)
+		if duration < minGrowthDuration {
+			continue
+		}
+
+		increasing := 0
+		for i := 1; i < len(segment); i++ {
+			if segment[i].MemoryUsage.Value() >= segment[i-1].MemoryUsage.Value() {
+				increasing++
+			}
+		}
+		from, to := float64(first.MemoryUsage.Value()), float64(last.MemoryUsage.Value())
+		if float64(increasing) < minIncreasingSteps*float64(len(segment)-1) || from <= 0 || to < from*minGrowthRatio {
+			continue
+		}
+
+		perHour := (to - from) / duration.Hours()
+		if perHour > steepest || (perHour == steepest && pod < result.Pod) {
+			steepest = perHour
+			result = GrowthResult{
+				Detected: true,
+				Pod:      pod,
+				From:     first.MemoryUsage,
+				To:       last.MemoryUsage,
+				Duration: duration,
+				PerHour:  *resource.NewQuantity(int64(perHour), resource.BinarySI),
+			}
+		}
+	}
+	return result
+}
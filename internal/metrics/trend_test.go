@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memory growth detection", func() {
+	var (
+		analyzer *Analyzer
+		start    time.Time
+	)
+
+	// series builds samples of a pod taken every 30 minutes with the given memory in MiB.
+	series := func(pod string, memory ...int) []UsageData {
+		samples := make([]UsageData, 0, len(memory))
+		for i, mib := range memory {
+			samples = append(samples, sample(pod, "100m", fmt.Sprintf("%dMi", mib), start.Add(time.Duration(i)*30*time.Minute)))
+		}
+		return samples
+	}
+
+	BeforeEach(func() {
+		analyzer = NewAnalyzer()
+		start = time.Now().Add(-24 * time.Hour)
+	})
+
+	It("Should detect steady growth since the last restart", func() {
+		metrics := &WorkloadMetrics{History: series("a", 400, 100, 120, 135, 150, 150, 170, 190)}
+
+		result := analyzer.DetectMemoryGrowth(metrics, 0)
+		Expect(result.Detected).To(BeTrue())
+		Expect(result.Pod).To(Equal("a"))
+		Expect(result.From.String()).To(Equal("100Mi"))
+		Expect(result.To.String()).To(Equal("190Mi"))
+		Expect(result.Duration).To(Equal(3 * time.Hour))
+	})
+
+	It("Should not flag memory that levels off or is freed again", func() {
+		stable := series("a", 100, 104, 101, 103, 102, 104, 103)
+		sawtooth := series("b", 100, 130, 160, 90, 120, 150, 95, 125)
+
+		Expect(analyzer.DetectMemoryGrowth(&WorkloadMetrics{History: stable}, 0).Detected).To(BeFalse())
+		Expect(analyzer.DetectMemoryGrowth(&WorkloadMetrics{History: sawtooth}, 0).Detected).To(BeFalse())
+	})
+
+	It("Should need enough samples to call it a trend", func() {
+		metrics := &WorkloadMetrics{History: series("a", 100, 150, 200)}
+		Expect(analyzer.DetectMemoryGrowth(metrics, 0).Detected).To(BeFalse())
+	})
+
+	It("Should leave out the startup grace period", func() {
+		samples := series("a", 100, 130, 160, 190, 220, 220, 221, 221, 222, 222)
+		for i := range samples {
+			samples[i].StartedAt = start
+		}
+		metrics := &WorkloadMetrics{History: samples}
+
+		Expect(analyzer.DetectMemoryGrowth(metrics, 0).Detected).To(BeTrue())
+		Expect(analyzer.DetectMemoryGrowth(metrics, 2*time.Hour).Detected).To(BeFalse())
+	})
+})
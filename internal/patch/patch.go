@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+const (
+	// KustomizeKey is the ConfigMap key holding a strategic-merge patch.
+	KustomizeKey = "patch.yaml"
+
+	// HelmKey is the ConfigMap key holding a Helm values snippet.
+	HelmKey = "values.yaml"
+)
+
+// Render returns the ConfigMap key and content of a patch that sets the given
+// resources on the container of the deployment in the requested format.
+func Render(output optimizationv1.PatchOutput, deployment *appsv1.Deployment, container string, resources corev1.ResourceRequirements, source string) (string, string, error) {
+	var (
+		key  string
+		body map[string]interface{}
+	)
+
+	switch output.Format {
+	case optimizationv1.PatchFormatHelm:
+		key = HelmKey
+		body = helmValues(output.ValuesPath, resources)
+	case optimizationv1.PatchFormatKustomize, "":
+		key = KustomizeKey
+		body = strategicMergePatch(deployment, container, resources)
+	default:
+		return "", "", fmt.Errorf("unsupported patch format %q", output.Format)
+	}
+
+	content, err := yaml.Marshal(body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	header := fmt.Sprintf("# Generated by cost-optimizer-operator from ResourceOptimizer %s.\n"+
+		"# Target: Deployment %s/%s, container %q.\n", source, deployment.Namespace, deployment.Name, container)
+	return key, header + string(content), nil
+}
+
+func strategicMergePatch(deployment *appsv1.Deployment, container string, resources corev1.ResourceRequirements) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      deployment.Name,
+			"namespace": deployment.Namespace,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":      container,
+							"resources": resources,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func helmValues(valuesPath string, resources corev1.ResourceRequirements) map[string]interface{} {
+	if valuesPath == "" {
+		valuesPath = "resources"
+	}
+
+	// Build the nesting from the innermost key outwards, so "api.resources"
+	// becomes {api: {resources: ...}}.
+	keys := strings.Split(valuesPath, ".")
+	var value interface{} = resources
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+	return value.(map[string]interface{})
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Render", func() {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api-service", Namespace: "production"}}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("250m"),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+	header := "# Generated by cost-optimizer-operator from ResourceOptimizer production/api-optimizer.\n" +
+		"# Target: Deployment production/api-service, container \"api\".\n"
+
+	DescribeTable("Should render the patch in the requested format",
+		func(output optimizationv1.PatchOutput, expectedKey, expectedBody string) {
+			key, content, err := Render(output, deployment, "api", resources, "production/api-optimizer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(expectedKey))
+			Expect(content).To(Equal(header + expectedBody))
+		},
+		Entry("a strategic-merge patch by default", optimizationv1.PatchOutput{}, KustomizeKey, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api-service
+  namespace: production
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        resources:
+          limits:
+            memory: 512Mi
+          requests:
+            cpu: 250m
+            memory: 256Mi
+`),
+		Entry("a strategic-merge patch for Kustomize",
+			optimizationv1.PatchOutput{Format: optimizationv1.PatchFormatKustomize}, KustomizeKey, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api-service
+  namespace: production
+spec:
+  template:
+    spec:
+      containers:
+      - name: api
+        resources:
+          limits:
+            memory: 512Mi
+          requests:
+            cpu: 250m
+            memory: 256Mi
+`),
+		Entry("Helm values under resources by default",
+			optimizationv1.PatchOutput{Format: optimizationv1.PatchFormatHelm}, HelmKey, `resources:
+  limits:
+    memory: 512Mi
+  requests:
+    cpu: 250m
+    memory: 256Mi
+`),
+		Entry("Helm values under a nested values path",
+			optimizationv1.PatchOutput{Format: optimizationv1.PatchFormatHelm, ValuesPath: "api.server.resources"}, HelmKey, `api:
+  server:
+    resources:
+      limits:
+        memory: 512Mi
+      requests:
+        cpu: 250m
+        memory: 256Mi
+`),
+	)
+
+	It("Should reject an unknown format", func() {
+		_, _, err := Render(optimizationv1.PatchOutput{Format: "Jsonnet"}, deployment, "api", resources, "production/api-optimizer")
+		Expect(err).To(MatchError(ContainSubstring(`unsupported patch format "Jsonnet"`)))
+	})
+})
+
+var _ = Describe("helmValues", func() {
+	DescribeTable("Should nest the resources under the values path",
+		func(valuesPath string, path ...string) {
+			resources := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			}
+			var value interface{} = helmValues(valuesPath, resources)
+			for _, key := range path {
+				Expect(value).To(HaveKey(key))
+				value = value.(map[string]interface{})[key]
+			}
+			Expect(value).To(Equal(resources))
+		},
+		Entry("defaulting to resources", "", "resources"),
+		Entry("at the top level", "limits", "limits"),
+		Entry("several levels deep", "global.api.resources", "global", "api", "resources"),
+	)
+})
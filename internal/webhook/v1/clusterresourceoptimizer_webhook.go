@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// log is for logging in this package.
+var clusterresourceoptimizerlog = logf.Log.WithName("clusterresourceoptimizer-resource")
+
+// SetupClusterResourceOptimizerWebhookWithManager registers the webhook for ClusterResourceOptimizer in the manager.
+func SetupClusterResourceOptimizerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&optimizationv1.ClusterResourceOptimizer{}).
+		WithValidator(&ClusterResourceOptimizerCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-optimization-stackbalancer-io-v1-clusterresourceoptimizer,mutating=false,failurePolicy=fail,sideEffects=None,groups=optimization.stackbalancer.io,resources=clusterresourceoptimizers,verbs=create;update,versions=v1,name=vclusterresourceoptimizer-v1.kb.io,admissionReviewVersions=v1
+
+// ClusterResourceOptimizerCustomValidator rejects ClusterResourceOptimizers
+// the controller cannot act on: an unsupported kind, invalid selectors and the
+// same policy errors as for ResourceOptimizers.
+type ClusterResourceOptimizerCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClusterResourceOptimizerCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterResourceOptimizer.
+func (v *ClusterResourceOptimizerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusterresourceoptimizer, ok := obj.(*optimizationv1.ClusterResourceOptimizer)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterResourceOptimizer object but got %T", obj)
+	}
+	clusterresourceoptimizerlog.Info("Validation for ClusterResourceOptimizer upon creation", "name", clusterresourceoptimizer.GetName())
+
+	return nil, validateClusterResourceOptimizer(clusterresourceoptimizer)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterResourceOptimizer.
+func (v *ClusterResourceOptimizerCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	clusterresourceoptimizer, ok := newObj.(*optimizationv1.ClusterResourceOptimizer)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterResourceOptimizer object for the newObj but got %T", newObj)
+	}
+	clusterresourceoptimizerlog.Info("Validation for ClusterResourceOptimizer upon update", "name", clusterresourceoptimizer.GetName())
+
+	return nil, validateClusterResourceOptimizer(clusterresourceoptimizer)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterResourceOptimizer.
+func (v *ClusterResourceOptimizerCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateClusterResourceOptimizer(clusterresourceoptimizer *optimizationv1.ClusterResourceOptimizer) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	spec := clusterresourceoptimizer.Spec
+	if spec.Kind != "" && !slices.Contains(supportedTargetKinds, spec.Kind) {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("kind"), spec.Kind, supportedTargetKinds))
+	}
+	options := metav1validation.LabelSelectorValidationOptions{}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&spec.Selector, options, specPath.Child("selector"))...)
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&spec.NamespaceSelector, options, specPath.Child("namespaceSelector"))...)
+	allErrs = append(allErrs, validatePolicy(spec.Policy, specPath.Child("policy"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		optimizationv1.GroupVersion.WithKind("ClusterResourceOptimizer").GroupKind(),
+		clusterresourceoptimizer.Name, allErrs)
+}
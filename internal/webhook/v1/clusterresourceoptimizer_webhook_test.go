@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("ClusterResourceOptimizer Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *optimizationv1.ClusterResourceOptimizer
+		validator *ClusterResourceOptimizerCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &optimizationv1.ClusterResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-defaults"},
+			Spec: optimizationv1.ClusterResourceOptimizerSpec{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}},
+				Policy: optimizationv1.Policy{
+					Cpu: optimizationv1.CPUPolicy{Min: "100m", Max: "1", TargetUtilization: 70},
+				},
+			},
+		}
+		validator = &ClusterResourceOptimizerCustomValidator{}
+	})
+
+	Context("When creating or updating ClusterResourceOptimizer under Validating Webhook", func() {
+		It("Should admit a valid ClusterResourceOptimizer", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny an update if cpu.min is greater than cpu.max", func() {
+			updated := obj.DeepCopy()
+			updated.Spec.Policy.Cpu.Min = "2"
+			_, err := validator.ValidateUpdate(ctx, obj, updated)
+			Expect(err).To(MatchError(ContainSubstring("must not be greater than max")))
+		})
+
+		It("Should deny a memory step of zero", func() {
+			obj.Spec.Policy.Memory.Step = "0"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.memory.step")))
+		})
+
+		It("Should deny a negative startup grace period", func() {
+			obj.Spec.Policy.StartupGracePeriod = &metav1.Duration{Duration: -time.Minute}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.startupGracePeriod")))
+		})
+
+		It("Should deny an idle period longer than the usage history", func() {
+			obj.Spec.Policy.Idle = &optimizationv1.IdlePolicy{Period: &metav1.Duration{Duration: 30 * 24 * time.Hour}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.idle.period")))
+		})
+
+		It("Should deny an unsupported kind", func() {
+			obj.Spec.Kind = "StatefulSet"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.kind")))
+		})
+
+		It("Should deny an invalid namespace selector", func() {
+			obj.Spec.NamespaceSelector = metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: "Matches", Values: []string{"payments"}},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.namespaceSelector")))
+		})
+	})
+})
@@ -0,0 +1,32 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// podsMutatedTotal counts pods that received recommended resources at admission.
+var podsMutatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cost_optimizer_pods_mutated_total",
+	Help: "Pods that received recommended resources at admission, by optimizer.",
+}, []string{"optimizer"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(podsMutatedTotal)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+// log is for logging in this package.
+var podlog = logf.Log.WithName("pod-resource")
+
+// SetupPodWebhookWithManager registers the webhook for Pod in the manager.
+func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
+		WithDefaulter(&PodCustomDefaulter{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.kb.io,admissionReviewVersions=v1
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// PodCustomDefaulter injects the current recommendation of a ResourceOptimizer
+// running in "Initial" update mode into pods of its target when they are created.
+// The owning workload is never modified, so GitOps tooling does not see drift.
+type PodCustomDefaulter struct {
+	Client client.Client
+}
+
+var _ webhook.CustomDefaulter = &PodCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Pod.
+//
+// Lookup failures are logged and swallowed: admitting a pod with its original
+// resources is always preferable to blocking workload rollouts.
+func (d *PodCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+
+	namespace := pod.Namespace
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Namespace != "" {
+		namespace = req.Namespace
+	}
+
+	deploymentName := optimizationv1.OwningDeploymentName(pod)
+	if deploymentName == "" {
+		return nil
+	}
+
+	optimizer, recommendation, err := d.findOptimizer(ctx, namespace, deploymentName)
+	if err != nil {
+		podlog.Error(err, "Failed to look up ResourceOptimizers", "namespace", namespace, "deployment", deploymentName)
+		return nil
+	}
+	if optimizer == nil {
+		return nil
+	}
+
+	// Honor the opt-out and pinning annotations of the workload immediately,
+	// before the controller has caught up with them.
+	deployment := &appsv1.Deployment{}
+	if err := d.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: deploymentName}, deployment); err != nil {
+		podlog.Error(err, "Failed to get owning Deployment", "namespace", namespace, "deployment", deploymentName)
+		return nil
+	}
+	if optimizationv1.AnnotationEnabled(deployment.Annotations, optimizationv1.AnnotationExclude) {
+		return nil
+	}
+
+	managed := optimizationv1.ManagedContainers(deployment.Annotations, pod.Spec.Containers)
+	if len(managed) != 1 {
+		podlog.Info("Skipping pod without a single managed container, recommendations are computed per pod",
+			"namespace", namespace, "deployment", deploymentName, "containers", len(managed))
+		return nil
+	}
+
+	resources, err := recommendation.ResourceRequirements()
+	if err != nil {
+		podlog.Error(err, "Ignoring invalid recommendation", "optimizer", client.ObjectKeyFromObject(optimizer))
+		return nil
+	}
+	if optimizationv1.AnnotationEnabled(deployment.Annotations, optimizationv1.AnnotationPinCPU) {
+		delete(resources.Requests, corev1.ResourceCPU)
+		delete(resources.Limits, corev1.ResourceCPU)
+	}
+	if optimizationv1.AnnotationEnabled(deployment.Annotations, optimizationv1.AnnotationPinMemory) {
+		delete(resources.Requests, corev1.ResourceMemory)
+		delete(resources.Limits, corev1.ResourceMemory)
+	}
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 {
+		return nil
+	}
+
+	var container *corev1.Container
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == managed[0] {
+			container = &pod.Spec.Containers[i]
+		}
+	}
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	for name, quantity := range resources.Requests {
+		container.Resources.Requests[name] = quantity
+	}
+	for name, quantity := range resources.Limits {
+		container.Resources.Limits[name] = quantity
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[optimizationv1.AnnotationOptimizedBy] = client.ObjectKeyFromObject(optimizer).String()
+	podsMutatedTotal.WithLabelValues(client.ObjectKeyFromObject(optimizer).String()).Inc()
+
+	podlog.Info("Injected recommended resources", "namespace", namespace, "deployment", deploymentName,
+		"optimizer", client.ObjectKeyFromObject(optimizer), "container", container.Name)
+	return nil
+}
+
+// findOptimizer returns the active ResourceOptimizer in "Initial" mode that targets
+// the given Deployment, by targetRef or targetSelector, together with its
+// recommendation for it. Optimizers from other namespaces are ignored unless
+// the pod's namespace allows them, as are recommendations at risk of being
+// rejected by admission. When several match, the oldest one wins so the
+// choice is stable across pods.
+func (d *PodCustomDefaulter) findOptimizer(ctx context.Context, namespace, deploymentName string) (*optimizationv1.ResourceOptimizer, *optimizationv1.ResourceRecommendation, error) {
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := d.Client.List(ctx, optimizers); err != nil {
+		return nil, nil, err
+	}
+
+	targetNamespace := &corev1.Namespace{}
+	if err := d.Client.Get(ctx, client.ObjectKey{Name: namespace}, targetNamespace); err != nil {
+		return nil, nil, err
+	}
+
+	target := optimizationv1.TargetRef{Kind: "Deployment", Name: deploymentName, Namespace: namespace}
+	var candidates []optimizationv1.ResourceOptimizer
+	for _, optimizer := range optimizers.Items {
+		if optimizer.Spec.UpdateMode != optimizationv1.UpdateModeInitial || optimizer.Spec.Suspend ||
+			optimizer.RecommendationFor(target) == nil {
+			continue
+		}
+		// Pods with resources the API server refuses would stall the rollout
+		if meta.IsStatusConditionTrue(optimizer.TargetConditions(target), optimizationv1.ConditionAdmissionRisk) {
+			continue
+		}
+		if !optimizationv1.SourceNamespaceAllowed(namespace, targetNamespace.Annotations, optimizer.Namespace) {
+			continue
+		}
+		candidates = append(candidates, optimizer)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].CreationTimestamp.Equal(&candidates[j].CreationTimestamp) {
+			return candidates[i].CreationTimestamp.Before(&candidates[j].CreationTimestamp)
+		}
+		return client.ObjectKeyFromObject(&candidates[i]).String() < client.ObjectKeyFromObject(&candidates[j]).String()
+	})
+	return &candidates[0], candidates[0].RecommendationFor(target), nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("Pod Webhook", func() {
+	var (
+		ctx        context.Context
+		pod        *corev1.Pod
+		deployment *appsv1.Deployment
+		optimizer  *optimizationv1.ResourceOptimizer
+		defaulter  *PodCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "api-service-7d9f-",
+				Namespace:    "production",
+				Labels:       map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "7d9f"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "ReplicaSet",
+					Name:       "api-service-7d9f",
+					UID:        "replicaset-uid",
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "api",
+					Image: "nginx:1.21",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("100m"),
+							corev1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				}},
+			},
+		}
+
+		optimizer = &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-service-optimizer",
+				Namespace: "maintenance",
+			},
+			Spec: optimizationv1.ResourceOptimizerSpec{
+				TargetRef: &optimizationv1.TargetRef{
+					Kind:      "Deployment",
+					Name:      "api-service",
+					Namespace: "production",
+				},
+				UpdateMode: optimizationv1.UpdateModeInitial,
+			},
+			Status: optimizationv1.ResourceOptimizerStatus{
+				CurrentRecommendation: &optimizationv1.ResourceRecommendation{
+					CPU:    optimizationv1.CPURecommendation{Request: "250m", Limit: "500m"},
+					Memory: optimizationv1.MemoryRecommendation{Request: "200Mi", Limit: "240Mi"},
+				},
+			},
+		}
+
+		production := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "production",
+				Annotations: map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "maintenance"},
+			},
+		}
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-service", Namespace: "production"},
+		}
+
+		defaulter = &PodCustomDefaulter{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).
+				WithObjects(production, deployment, optimizer).Build(),
+		}
+	})
+
+	Context("When creating a Pod of an optimized Deployment", func() {
+		It("Should inject the recommended resources", func() {
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+
+			resources := pod.Spec.Containers[0].Resources
+			Expect(resources.Requests.Cpu().String()).To(Equal("250m"))
+			Expect(resources.Requests.Memory().String()).To(Equal("200Mi"))
+			Expect(resources.Limits.Cpu().String()).To(Equal("500m"))
+			Expect(resources.Limits.Memory().String()).To(Equal("240Mi"))
+			Expect(pod.Annotations).To(HaveKeyWithValue(optimizationv1.AnnotationOptimizedBy,
+				"maintenance/api-service-optimizer"))
+		})
+	})
+
+	Context("When the Deployment is matched by a target selector", func() {
+		It("Should inject the recommendation listed for it", func() {
+			optimizer.Spec.TargetRef = nil
+			optimizer.Spec.TargetSelector = &optimizationv1.TargetSelector{Kind: "Deployment"}
+			optimizer.Status.Targets = []optimizationv1.TargetStatus{{
+				TargetRef:      optimizationv1.TargetRef{Kind: "Deployment", Name: "api-service", Namespace: "production"},
+				Recommendation: optimizer.Status.CurrentRecommendation,
+			}}
+			optimizer.Status.CurrentRecommendation = nil
+			Expect(defaulter.Client.Update(ctx, optimizer)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("250m"))
+		})
+
+		It("Should leave the Pod untouched when the Deployment is not listed", func() {
+			optimizer.Spec.TargetRef = nil
+			optimizer.Spec.TargetSelector = &optimizationv1.TargetSelector{Kind: "Deployment"}
+			Expect(defaulter.Client.Update(ctx, optimizer)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+		})
+	})
+
+	Context("When the Pod namespace does not allow the optimizer", func() {
+		It("Should leave the Pod untouched", func() {
+			production := &corev1.Namespace{}
+			Expect(defaulter.Client.Get(ctx, client.ObjectKey{Name: "production"}, production)).To(Succeed())
+			production.Annotations = nil
+			Expect(defaulter.Client.Update(ctx, production)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+		})
+	})
+
+	Context("When the optimizer is suspended", func() {
+		It("Should leave the Pod untouched", func() {
+			optimizer.Spec.Suspend = true
+			Expect(defaulter.Client.Update(ctx, optimizer)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+		})
+	})
+
+	Context("When the optimizer is not in Initial mode", func() {
+		It("Should leave the Pod untouched", func() {
+			optimizer.Spec.UpdateMode = optimizationv1.UpdateModeOff
+			Expect(defaulter.Client.Update(ctx, optimizer)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+			Expect(pod.Annotations).NotTo(HaveKey(optimizationv1.AnnotationOptimizedBy))
+		})
+	})
+
+	Context("When the recommendation would be rejected by admission", func() {
+		It("Should leave the Pod untouched", func() {
+			optimizer.Status.Conditions = []metav1.Condition{{
+				Type:               optimizationv1.ConditionAdmissionRisk,
+				Status:             metav1.ConditionTrue,
+				Reason:             "QuotaExceeded",
+				LastTransitionTime: metav1.Now(),
+			}}
+			Expect(defaulter.Client.Update(ctx, optimizer)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+		})
+	})
+
+	Context("When the Pod is not owned by a Deployment", func() {
+		It("Should leave the Pod untouched", func() {
+			pod.OwnerReferences = nil
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+		})
+	})
+
+	Context("When the Pod has several containers", func() {
+		BeforeEach(func() {
+			pod.Spec.Containers = append([]corev1.Container{{Name: "sidecar", Image: "envoy"}}, pod.Spec.Containers...)
+		})
+
+		It("Should leave the Pod untouched", func() {
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[1].Resources.Requests.Cpu().String()).To(Equal("100m"))
+		})
+
+		It("Should resize the only container not excluded by annotation", func() {
+			deployment.Annotations = map[string]string{optimizationv1.AnnotationExcludeContainers: "sidecar"}
+			Expect(defaulter.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+			Expect(pod.Spec.Containers[1].Resources.Requests.Cpu().String()).To(Equal("250m"))
+		})
+	})
+
+	Context("When the Deployment carries optimization annotations", func() {
+		It("Should leave an excluded workload untouched", func() {
+			deployment.Annotations = map[string]string{optimizationv1.AnnotationExclude: "true"}
+			Expect(defaulter.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Resources.Requests.Cpu().String()).To(Equal("100m"))
+			Expect(pod.Annotations).NotTo(HaveKey(optimizationv1.AnnotationOptimizedBy))
+		})
+
+		It("Should keep pinned resources", func() {
+			deployment.Annotations = map[string]string{optimizationv1.AnnotationPinCPU: "true"}
+			Expect(defaulter.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(defaulter.Default(ctx, pod)).To(Succeed())
+			resources := pod.Spec.Containers[0].Resources
+			Expect(resources.Requests.Cpu().String()).To(Equal("100m"))
+			Expect(resources.Limits).NotTo(HaveKey(corev1.ResourceCPU))
+			Expect(resources.Requests.Memory().String()).To(Equal("200Mi"))
+		})
+	})
+})
@@ -0,0 +1,302 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+	"github.com/stackbalancer/cost-optimizer-operator/internal/metrics"
+)
+
+// log is for logging in this package.
+var resourceoptimizerlog = logf.Log.WithName("resourceoptimizer-resource")
+
+// supportedTargetKinds lists the workload kinds the controller knows how to analyze.
+var supportedTargetKinds = []string{"Deployment"}
+
+// SetupResourceOptimizerWebhookWithManager registers the webhook for ResourceOptimizer in the manager.
+func SetupResourceOptimizerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&optimizationv1.ResourceOptimizer{}).
+		WithValidator(&ResourceOptimizerCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-optimization-stackbalancer-io-v1-resourceoptimizer,mutating=false,failurePolicy=fail,sideEffects=None,groups=optimization.stackbalancer.io,resources=resourceoptimizers,verbs=create;update,versions=v1,name=vresourceoptimizer-v1.kb.io,admissionReviewVersions=v1
+
+// ResourceOptimizerCustomValidator rejects ResourceOptimizers the controller
+// cannot act on: missing, ambiguous, unsupported or incomplete targets, unparseable or inverted
+// CPU bounds, and a second optimizer for a workload that already has one.
+type ResourceOptimizerCustomValidator struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &ResourceOptimizerCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ResourceOptimizer.
+func (v *ResourceOptimizerCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	resourceoptimizer, ok := obj.(*optimizationv1.ResourceOptimizer)
+	if !ok {
+		return nil, fmt.Errorf("expected a ResourceOptimizer object but got %T", obj)
+	}
+	resourceoptimizerlog.Info("Validation for ResourceOptimizer upon creation", "name", resourceoptimizer.GetName())
+
+	return nil, v.validateResourceOptimizer(ctx, resourceoptimizer)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ResourceOptimizer.
+func (v *ResourceOptimizerCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	resourceoptimizer, ok := newObj.(*optimizationv1.ResourceOptimizer)
+	if !ok {
+		return nil, fmt.Errorf("expected a ResourceOptimizer object for the newObj but got %T", newObj)
+	}
+	resourceoptimizerlog.Info("Validation for ResourceOptimizer upon update", "name", resourceoptimizer.GetName())
+
+	return nil, v.validateResourceOptimizer(ctx, resourceoptimizer)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ResourceOptimizer.
+func (v *ResourceOptimizerCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ResourceOptimizerCustomValidator) validateResourceOptimizer(ctx context.Context, resourceoptimizer *optimizationv1.ResourceOptimizer) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	spec := resourceoptimizer.Spec
+	switch {
+	case spec.TargetRef != nil && spec.TargetSelector != nil:
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("targetSelector"), "may not be set together with targetRef"))
+	case spec.TargetRef != nil:
+		allErrs = append(allErrs, validateTargetRef(*spec.TargetRef, specPath.Child("targetRef"))...)
+	case spec.TargetSelector != nil:
+		allErrs = append(allErrs, validateTargetSelector(*spec.TargetSelector, specPath.Child("targetSelector"))...)
+	default:
+		allErrs = append(allErrs, field.Required(specPath.Child("targetRef"), "one of targetRef or targetSelector is required"))
+	}
+	allErrs = append(allErrs, validatePolicy(resourceoptimizer.Spec.Policy, specPath.Child("policy"))...)
+	if resourceoptimizer.Spec.PatchOutput != nil {
+		allErrs = append(allErrs, validatePatchOutput(*resourceoptimizer.Spec.PatchOutput, specPath.Child("patchOutput"))...)
+	}
+
+	if len(allErrs) == 0 && spec.TargetRef != nil {
+		conflictErr, err := v.validateNoConflict(ctx, resourceoptimizer, specPath.Child("targetRef"))
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if conflictErr != nil {
+			allErrs = append(allErrs, conflictErr)
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		optimizationv1.GroupVersion.WithKind("ResourceOptimizer").GroupKind(),
+		resourceoptimizer.Name, allErrs)
+}
+
+func validateTargetRef(target optimizationv1.TargetRef, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !slices.Contains(supportedTargetKinds, target.Kind) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("kind"), target.Kind, supportedTargetKinds))
+	}
+	if target.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "target name must not be empty"))
+	}
+	if target.Namespace == "" {
+		allErrs = append(allErrs, field.Required(path.Child("namespace"), "target namespace must not be empty"))
+	}
+
+	return allErrs
+}
+
+func validateTargetSelector(target optimizationv1.TargetSelector, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if target.Kind != "" && !slices.Contains(supportedTargetKinds, target.Kind) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("kind"), target.Kind, supportedTargetKinds))
+	}
+	options := metav1validation.LabelSelectorValidationOptions{}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&target.Selector, options, path.Child("selector"))...)
+	if target.NamespaceSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(target.NamespaceSelector, options, path.Child("namespaceSelector"))...)
+	}
+
+	return allErrs
+}
+
+// validatePolicy checks the policy shared by ResourceOptimizers and
+// ClusterResourceOptimizers.
+func validatePolicy(policy optimizationv1.Policy, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateCPUPolicy(policy.Cpu, path.Child("cpu"))...)
+	allErrs = append(allErrs, validateStep(policy.Memory.Step, path.Child("memory", "step"))...)
+	if grace := policy.StartupGracePeriod; grace != nil && grace.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("startupGracePeriod"), grace.Duration.String(),
+			"must not be negative"))
+	}
+	if policy.Idle != nil {
+		allErrs = append(allErrs, validateIdlePolicy(*policy.Idle, path.Child("idle"))...)
+	}
+	if policy.Replicas != nil {
+		allErrs = append(allErrs, validateReplicaPolicy(*policy.Replicas, path.Child("replicas"))...)
+	}
+
+	return allErrs
+}
+
+func validateCPUPolicy(policy optimizationv1.CPUPolicy, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	minCPU, minErr := resource.ParseQuantity(policy.Min)
+	if minErr != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("min"), policy.Min, minErr.Error()))
+	}
+	maxCPU, maxErr := resource.ParseQuantity(policy.Max)
+	if maxErr != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("max"), policy.Max, maxErr.Error()))
+	}
+	if minErr == nil && maxErr == nil && minCPU.Cmp(maxCPU) > 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("min"), policy.Min,
+			fmt.Sprintf("must not be greater than max (%s)", policy.Max)))
+	}
+	allErrs = append(allErrs, validateStep(policy.Step, path.Child("step"))...)
+
+	return allErrs
+}
+
+// validateStep checks that a rounding step, if set, is a positive quantity.
+func validateStep(step string, path *field.Path) field.ErrorList {
+	if step == "" {
+		return nil
+	}
+	quantity, err := resource.ParseQuantity(step)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, step, err.Error())}
+	}
+	if quantity.Sign() <= 0 {
+		return field.ErrorList{field.Invalid(path, step, "must be greater than zero")}
+	}
+	return nil
+}
+
+func validateReplicaPolicy(policy optimizationv1.ReplicaPolicy, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if policy.TargetPodCPU != "" {
+		if _, err := resource.ParseQuantity(policy.TargetPodCPU); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("targetPodCPU"), policy.TargetPodCPU, err.Error()))
+		}
+	}
+	if policy.TargetPodMemory != "" {
+		if _, err := resource.ParseQuantity(policy.TargetPodMemory); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("targetPodMemory"), policy.TargetPodMemory, err.Error()))
+		}
+	}
+	if policy.MaxReplicas > 0 && policy.MinReplicas > policy.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(path.Child("minReplicas"), policy.MinReplicas,
+			fmt.Sprintf("must not be greater than maxReplicas (%d)", policy.MaxReplicas)))
+	}
+
+	return allErrs
+}
+
+func validateIdlePolicy(policy optimizationv1.IdlePolicy, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if policy.Period != nil {
+		if period := policy.Period.Duration; period < 0 || period > metrics.DefaultHistoryRetention {
+			allErrs = append(allErrs, field.Invalid(path.Child("period"), period.String(),
+				fmt.Sprintf("must be between 0s and %s, the usage history kept by the operator", metrics.DefaultHistoryRetention)))
+		}
+	}
+	if policy.CPUThreshold != "" {
+		if _, err := resource.ParseQuantity(policy.CPUThreshold); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("cpuThreshold"), policy.CPUThreshold, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validatePatchOutput(output optimizationv1.PatchOutput, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if output.ValuesPath != "" {
+		for _, key := range strings.Split(output.ValuesPath, ".") {
+			if key == "" {
+				allErrs = append(allErrs, field.Invalid(path.Child("valuesPath"), output.ValuesPath,
+					"must be a dot-separated path without empty segments"))
+				break
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateNoConflict rejects a second ResourceOptimizer for a workload that is
+// already targeted, since both would publish competing recommendations. Only
+// targetRefs are compared; a targetRef takes precedence over selectors
+// matching the same workload.
+func (v *ResourceOptimizerCustomValidator) validateNoConflict(ctx context.Context, resourceoptimizer *optimizationv1.ResourceOptimizer, path *field.Path) (*field.Error, error) {
+	optimizers := &optimizationv1.ResourceOptimizerList{}
+	if err := v.Client.List(ctx, optimizers); err != nil {
+		return nil, err
+	}
+
+	target := *resourceoptimizer.Spec.TargetRef
+	namespace := &corev1.Namespace{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Name: target.Namespace}, namespace); client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	for _, other := range optimizers.Items {
+		if other.Namespace == resourceoptimizer.Namespace && other.Name == resourceoptimizer.Name {
+			continue
+		}
+		// An optimizer the target namespace does not grant access never acts
+		// on the workload, so it must not block one that is allowed to
+		if !optimizationv1.SourceNamespaceAllowed(target.Namespace, namespace.Annotations, other.Namespace) {
+			continue
+		}
+		if other.Spec.TargetRef != nil && *other.Spec.TargetRef == target {
+			return field.Forbidden(path, fmt.Sprintf("%s %s/%s is already targeted by ResourceOptimizer %s/%s",
+				target.Kind, target.Namespace, target.Name, other.Namespace, other.Name)), nil
+		}
+	}
+	return nil, nil
+}
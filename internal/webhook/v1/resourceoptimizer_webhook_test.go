@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	optimizationv1 "github.com/stackbalancer/cost-optimizer-operator/api/v1"
+)
+
+var _ = Describe("ResourceOptimizer Webhook", func() {
+	var (
+		ctx       context.Context
+		obj       *optimizationv1.ResourceOptimizer
+		validator *ResourceOptimizerCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		obj = &optimizationv1.ResourceOptimizer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-service-optimizer",
+				Namespace: "maintenance",
+			},
+			Spec: optimizationv1.ResourceOptimizerSpec{
+				TargetRef: &optimizationv1.TargetRef{
+					Kind:      "Deployment",
+					Name:      "api-service",
+					Namespace: "production",
+				},
+				Policy: optimizationv1.Policy{
+					Cpu: optimizationv1.CPUPolicy{Min: "200m", Max: "800m", TargetUtilization: 70},
+				},
+			},
+		}
+		validator = &ResourceOptimizerCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).Build(),
+		}
+	})
+
+	Context("When creating or updating ResourceOptimizer under Validating Webhook", func() {
+		It("Should admit a valid ResourceOptimizer", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny creation if cpu.min is greater than cpu.max", func() {
+			obj.Spec.Policy.Cpu.Min = "2"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("must not be greater than max")))
+		})
+
+		It("Should deny creation if a quantity cannot be parsed", func() {
+			obj.Spec.Policy.Cpu.Max = "lots"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.cpu.max")))
+		})
+
+		It("Should deny an idle period longer than the usage history", func() {
+			obj.Spec.Policy.Idle = &optimizationv1.IdlePolicy{Period: &metav1.Duration{Duration: 30 * 24 * time.Hour}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.idle.period")))
+		})
+
+		It("Should deny a rounding step of zero", func() {
+			obj.Spec.Policy.Memory.Step = "0"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.memory.step")))
+		})
+
+		It("Should deny a negative startup grace period", func() {
+			obj.Spec.Policy.StartupGracePeriod = &metav1.Duration{Duration: -time.Minute}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.startupGracePeriod")))
+		})
+
+		It("Should deny creation of an unsupported target kind", func() {
+			obj.Spec.TargetRef.Kind = "CronJob"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.targetRef.kind")))
+		})
+
+		It("Should deny creation if the target name is empty", func() {
+			obj.Spec.TargetRef.Name = ""
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.targetRef.name")))
+		})
+
+		It("Should deny creation without a target", func() {
+			obj.Spec.TargetRef = nil
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("one of targetRef or targetSelector is required")))
+		})
+
+		It("Should deny creation with both a target and a selector", func() {
+			obj.Spec.TargetSelector = &optimizationv1.TargetSelector{Kind: "Deployment"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.targetSelector")))
+		})
+
+		It("Should admit a valid target selector", func() {
+			obj.Spec.TargetRef = nil
+			obj.Spec.TargetSelector = &optimizationv1.TargetSelector{
+				Kind:              "Deployment",
+				Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "production"}},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny creation of an invalid target selector", func() {
+			obj.Spec.TargetRef = nil
+			obj.Spec.TargetSelector = &optimizationv1.TargetSelector{
+				Kind: "Deployment",
+				Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key: "team", Operator: metav1.LabelSelectorOpIn,
+				}}},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.targetSelector.selector.matchExpressions[0].values")))
+		})
+
+		It("Should deny creation if another optimizer targets the same workload", func() {
+			Expect(validator.Client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "production",
+				Annotations: map[string]string{optimizationv1.AnnotationAllowedSourceNamespaces: "maintenance"},
+			}})).To(Succeed())
+			existing := obj.DeepCopy()
+			existing.Name = "existing-optimizer"
+			Expect(validator.Client.Create(ctx, existing)).To(Succeed())
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("already targeted by ResourceOptimizer maintenance/existing-optimizer")))
+		})
+
+		It("Should ignore an optimizer the target namespace does not grant access", func() {
+			Expect(validator.Client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}})).To(Succeed())
+			existing := obj.DeepCopy()
+			existing.Name = "existing-optimizer"
+			Expect(validator.Client.Create(ctx, existing)).To(Succeed())
+
+			owned := obj.DeepCopy()
+			owned.Namespace = "production"
+			Expect(validator.ValidateCreate(ctx, owned)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit an update of the optimizer that owns the target", func() {
+			Expect(validator.Client.Create(ctx, obj.DeepCopy())).To(Succeed())
+
+			updated := obj.DeepCopy()
+			updated.Spec.Policy.Cpu.Max = "1"
+			Expect(validator.ValidateUpdate(ctx, obj, updated)).Error().NotTo(HaveOccurred())
+		})
+	})
+})